@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/bundle"
+
+	"github.com/spf13/cobra"
+)
+
+// bundleCmd represents the bundle command
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Export and import offline scenario bundles",
+	Long: `A bundle carries everything a class needs to run scenarios without
+internet: the chart repositories, the ScenarioDefinitions pinned to the
+bundled commit, the list of container images and optionally the images
+themselves. Bundled scenarios are meant to be started with --mode direct.`,
+}
+
+var bundleCreateCmd = &cobra.Command{
+	Use:     "create",
+	Short:   "Create a bundle from scenarios registered in the cluster",
+	Example: "  dbeerer bundle create --scenarios scenario-1,scenario-2 -o class.tar.gz --images",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var opts bundle.CreateOptions
+		opts.Scenarios, _ = cmd.Flags().GetStringSlice("scenarios")
+		opts.Output, _ = cmd.Flags().GetString("output")
+		opts.IncludeImages, _ = cmd.Flags().GetBool("images")
+		opts.Platform, _ = cmd.Flags().GetString("platform")
+
+		manager, err := bundle.NewManager(cfg)
+		if err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+
+		if err := manager.Create(opts); err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+		return nil
+	},
+}
+
+var bundleImportCmd = &cobra.Command{
+	Use:   "import <bundle>",
+	Short: "Load a bundle into the chart cache and the cluster",
+	Long: `Store the bundled chart repositories in the chart cache, apply the
+ScenarioDefinitions and import the bundled images into K3s with
+'k3s ctr images import', which needs root.`,
+	Example: "  sudo dbeerer bundle import class.tar.gz",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var opts bundle.ImportOptions
+		opts.SkipImages, _ = cmd.Flags().GetBool("skip-images")
+
+		manager, err := bundle.NewManager(cfg)
+		if err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+
+		if err := manager.Import(args[0], opts); err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	bundleCreateCmd.Flags().StringSlice("scenarios", nil, "IDs of the scenarios to bundle (comma separated)")
+	bundleCreateCmd.Flags().StringP("output", "o", "bundle.tar.gz", "Path of the bundle to write")
+	bundleCreateCmd.Flags().Bool("images", false, "Include the container images as an OCI archive")
+	bundleCreateCmd.Flags().String("platform", "linux/"+runtime.GOARCH, "Platform of the images to include (os/arch)")
+	_ = bundleCreateCmd.MarkFlagRequired("scenarios")
+	_ = bundleCreateCmd.RegisterFlagCompletionFunc("scenarios", completeScenarioIDs)
+
+	bundleImportCmd.Flags().Bool("skip-images", false, "Don't import the bundled images")
+
+	bundleCmd.AddCommand(bundleCreateCmd)
+	bundleCmd.AddCommand(bundleImportCmd)
+	rootCmd.AddCommand(bundleCmd)
+}
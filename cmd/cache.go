@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/cache"
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/progress"
+
+	"github.com/spf13/cobra"
+)
+
+// cacheCmd represents the cache command
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the local chart cache",
+	Long: `Downloaded chart repositories are kept under the user cache directory
+(or DBEERER_CACHE_DIR), keyed by repository and ref. Later downloads only
+revalidate them with the server, and fall back to them when offline.`,
+}
+
+var cacheListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List cached chart repositories",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entries, err := cache.New().List()
+		if err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+
+		if len(entries) == 0 {
+			fmt.Println("ℹ️  The chart cache is empty")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "REPOSITORY\tREF\tDIGEST\tSIZE\tFETCHED\tLAST USED")
+		for _, entry := range entries {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				entry.Repo,
+				entry.Ref,
+				shortDigest(entry.Digest),
+				progress.FormatBytes(entry.Size),
+				entry.FetchedAt.Format(time.RFC3339),
+				entry.LastUsed.Format(time.RFC3339),
+			)
+		}
+		return w.Flush()
+	},
+}
+
+// shortDigest abbreviates a blob digest for display, leaving short or
+// missing ones from damaged entries as they are
+func shortDigest(digest string) string {
+	if digest == "" {
+		return "-"
+	}
+	if len(digest) > 12 {
+		return digest[:12]
+	}
+	return digest
+}
+
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove cached chart repositories not used recently",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		olderThan, _ := cmd.Flags().GetDuration("older-than")
+
+		freed, err := cache.New().Prune(olderThan)
+		if err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+
+		fmt.Printf("✅ Freed %s\n", progress.FormatBytes(freed))
+		return nil
+	},
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove the whole chart cache",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		chartCache := cache.New()
+		if err := chartCache.Clear(); err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+
+		fmt.Printf("✅ Cleared %s\n", chartCache.Dir())
+		return nil
+	},
+}
+
+func init() {
+	cachePruneCmd.Flags().Duration("older-than", 30*24*time.Hour, "Remove repositories not used for this long")
+
+	cacheCmd.AddCommand(cacheListCmd)
+	cacheCmd.AddCommand(cachePruneCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/scenarios"
+	"github.com/DevOpsBeerer/dbeerer-cli/pkg/apis/devopsbeerer/v1alpha1"
+
+	"github.com/spf13/cobra"
+)
+
+// Where the upstream catalogue is read from
+const (
+	catalogFromMetadata = "metadata"
+	catalogFromCharts   = "charts"
+)
+
+// catalogCmd represents the catalog command
+var catalogCmd = &cobra.Command{
+	Use:   "catalog",
+	Short: "Compare and sync the cluster's scenarios with the upstream catalogue",
+	Long: `The upstream catalogue is the metadata.json of the scenario charts
+repository (--from metadata, see --metadata-url) or the scenario.yaml next to
+every chart of the configured chart source (--from charts).`,
+}
+
+var catalogDiffCmd = &cobra.Command{
+	Use:     "diff",
+	Short:   "Show scenarios that are new, removed or changed upstream",
+	Example: "  dbeerer catalog diff\n  dbeerer catalog diff --from charts",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, changes, err := diffCatalog(cmd)
+		if err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+
+		printCatalogChanges(changes)
+		return nil
+	},
+}
+
+var catalogSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Apply the upstream scenario definitions to the cluster",
+	Long: `Create the scenarios that are new upstream and update the changed ones.
+Scenarios missing upstream, e.g. ones under development, are kept unless
+--prune is given.`,
+	Example: "  dbeerer catalog sync\n  dbeerer catalog sync --prune",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		prune, _ := cmd.Flags().GetBool("prune")
+
+		manager, changes, err := diffCatalog(cmd)
+		if err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+		printCatalogChanges(changes)
+
+		applied := 0
+		for _, change := range changes {
+			if change.Kind == scenarios.ChangeRemoved && !prune {
+				continue
+			}
+			if err := manager.ApplyCatalogChange(change); err != nil {
+				return fmt.Errorf("❌ %w", err)
+			}
+			applied++
+		}
+
+		if applied > 0 {
+			fmt.Printf("✅ Applied %d change(s) to the cluster\n", applied)
+		}
+		return nil
+	},
+}
+
+// diffCatalog loads the upstream catalogue selected by the flags and
+// compares it with the cluster
+func diffCatalog(cmd *cobra.Command) (*scenarios.Manager, []scenarios.CatalogChange, error) {
+	from, _ := cmd.Flags().GetString("from")
+	metadataURL, _ := cmd.Flags().GetString("metadata-url")
+
+	manager, err := scenarios.NewManager(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var upstream []*v1alpha1.ScenarioDefinition
+	switch from {
+	case catalogFromMetadata:
+		upstream, err = manager.FetchMetadataCatalog(metadataURL)
+	case catalogFromCharts:
+		upstream, err = manager.FetchChartCatalog()
+	default:
+		return nil, nil, fmt.Errorf("unknown catalogue '%s', expected %s or %s", from, catalogFromMetadata, catalogFromCharts)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	changes, err := manager.DiffCatalog(upstream)
+	if err != nil {
+		return nil, nil, err
+	}
+	return manager, changes, nil
+}
+
+// printCatalogChanges lists the changes with the fields that differ
+func printCatalogChanges(changes []scenarios.CatalogChange) {
+	if len(changes) == 0 {
+		fmt.Println("✅ The cluster's scenarios match the upstream catalogue")
+		return
+	}
+
+	counts := map[string]int{}
+	for _, change := range changes {
+		counts[change.Kind]++
+
+		switch change.Kind {
+		case scenarios.ChangeNew:
+			fmt.Printf("➕ %s (new upstream)\n", change.ID)
+		case scenarios.ChangeRemoved:
+			fmt.Printf("➖ %s (not upstream)\n", change.ID)
+		case scenarios.ChangeChanged:
+			fmt.Printf("✏️  %s (changed)\n", change.ID)
+			for _, field := range change.Fields {
+				fmt.Printf("     %s: %s → %s\n", field.Field, valueOrNone(field.Cluster), valueOrNone(field.Upstream))
+			}
+		}
+	}
+
+	fmt.Printf("ℹ️  %d new, %d changed, %d removed\n",
+		counts[scenarios.ChangeNew], counts[scenarios.ChangeChanged], counts[scenarios.ChangeRemoved])
+}
+
+func init() {
+	for _, command := range []*cobra.Command{catalogDiffCmd, catalogSyncCmd} {
+		command.Flags().String("from", catalogFromMetadata, "Upstream catalogue: metadata or charts")
+		command.Flags().String("metadata-url", scenarios.MetadataURL, "URL of the upstream metadata.json")
+		_ = command.RegisterFlagCompletionFunc("from", cobra.FixedCompletions([]string{catalogFromMetadata, catalogFromCharts}, cobra.ShellCompDirectiveNoFileComp))
+	}
+	catalogSyncCmd.Flags().Bool("prune", false, "Also delete scenarios missing upstream")
+
+	catalogCmd.AddCommand(catalogDiffCmd)
+	catalogCmd.AddCommand(catalogSyncCmd)
+	rootCmd.AddCommand(catalogCmd)
+}
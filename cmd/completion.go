@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/scenarios"
+
+	"github.com/spf13/cobra"
+)
+
+// completionCacheTTL is how long scenario completions are reused before
+// the cluster is queried again. Every TAB press runs a new process, so the
+// cache lives on disk
+const completionCacheTTL = 30 * time.Second
+
+// outputFormats lists the values accepted by --output
+var outputFormats = []string{"table", "wide", "json", "yaml"}
+
+// completionCache is the on-disk form of cached scenario completions
+type completionCache struct {
+	Kubeconfig string               `json:"kubeconfig"`
+	FetchedAt  time.Time            `json:"fetchedAt"`
+	Scenarios  []scenarios.Scenario `json:"scenarios"`
+}
+
+// completeScenarioIDs completes the first argument with scenario IDs,
+// using each scenario's name and description as the hint
+func completeScenarioIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	scenarioList, err := cachedScenarios(cmd)
+	if err != nil {
+		cobra.CompDebugln(err.Error(), true)
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	completions := make([]string, 0, len(scenarioList))
+	for _, scenario := range scenarioList {
+		if !strings.HasPrefix(scenario.ID, toComplete) {
+			continue
+		}
+		completions = append(completions, fmt.Sprintf("%s\t%s - %s", scenario.ID, scenario.Name, scenario.Description))
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeOutputFormats completes --output values
+func completeOutputFormats(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return outputFormats, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeScenarioTags completes --tag values with the tags in use
+func completeScenarioTags(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeScenarioField(cmd, func(s scenarios.Scenario) []string { return s.Tags })
+}
+
+// completeScenarioFeatures completes --feature values with the features in use
+func completeScenarioFeatures(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeScenarioField(cmd, func(s scenarios.Scenario) []string { return s.Features })
+}
+
+// completeScenarioField returns the distinct values of a list field across scenarios
+func completeScenarioField(cmd *cobra.Command, field func(scenarios.Scenario) []string) ([]string, cobra.ShellCompDirective) {
+	scenarioList, err := cachedScenarios(cmd)
+	if err != nil {
+		cobra.CompDebugln(err.Error(), true)
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	seen := map[string]bool{}
+	var values []string
+	for _, scenario := range scenarioList {
+		for _, value := range field(scenario) {
+			if !seen[value] {
+				seen[value] = true
+				values = append(values, value)
+			}
+		}
+	}
+	sort.Strings(values)
+
+	return values, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeSlots completes --name with the slots that have a scenario running
+// Slots change with every start and stop, so they are not cached
+func completeSlots(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	resolved, err := resolveConfig(cmd)
+	if err != nil {
+		cobra.CompDebugln(err.Error(), true)
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	manager, err := scenarios.NewManager(resolved)
+	if err != nil {
+		cobra.CompDebugln(err.Error(), true)
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	statuses, err := manager.ListActiveScenarios()
+	if err != nil {
+		cobra.CompDebugln(err.Error(), true)
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	completions := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		completions = append(completions, fmt.Sprintf("%s\t%s", status.Slot, status.ScenarioID))
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// cachedScenarios returns the scenario list, served from the completion
+// cache when it is fresh enough
+func cachedScenarios(cmd *cobra.Command) ([]scenarios.Scenario, error) {
+	resolved, err := resolveConfig(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	cachePath := completionCachePath()
+	if cache, err := readCompletionCache(cachePath); err == nil &&
+		cache.Kubeconfig == resolved.Kubeconfig &&
+		time.Since(cache.FetchedAt) < completionCacheTTL {
+		return cache.Scenarios, nil
+	}
+
+	manager, err := scenarios.NewManager(resolved)
+	if err != nil {
+		return nil, err
+	}
+
+	scenarioList, err := manager.ListScenarios()
+	if err != nil {
+		return nil, err
+	}
+
+	// Caching is best effort, completion still works without it
+	_ = writeCompletionCache(cachePath, &completionCache{
+		Kubeconfig: resolved.Kubeconfig,
+		FetchedAt:  time.Now(),
+		Scenarios:  scenarioList,
+	})
+
+	return scenarioList, nil
+}
+
+// completionCachePath returns the file holding cached scenario completions
+func completionCachePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "dbeerer", "completion-scenarios.json")
+}
+
+func readCompletionCache(path string) (*completionCache, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cache := &completionCache{}
+	if err := json.Unmarshal(data, cache); err != nil {
+		return nil, err
+	}
+	return cache, nil
+}
+
+func writeCompletionCache(path string, cache *completionCache) error {
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
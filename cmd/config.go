@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/config"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage CLI configuration and profiles",
+	Long: `Manage the dbeerer configuration file and its named profiles.
+Each value resolves from its flag, then its DBEERER_* environment variable,
+then the selected profile, then the top level of the config file, and finally
+the built-in default.`,
+	// Config commands must keep working when the config itself is broken
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the effective value of a config key",
+	Args:  cobra.ExactArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return config.Keys(), cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		resolved, err := resolveConfig(cmd)
+		if err != nil {
+			return err
+		}
+
+		value, err := resolved.Get(args[0])
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(value.Value)
+		return nil
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a config key in the selected profile",
+	Long: `Set a config key in the profile selected by --profile, or in the current
+profile. Without any profile the value is written at the top level of the
+config file, where it applies to every profile. The profile is created if it
+doesn't exist yet. An empty value unsets the key.`,
+	Args: cobra.ExactArgs(2),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return config.Keys(), cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := configPath(cmd)
+
+		file, err := config.Load(path)
+		if err != nil {
+			return err
+		}
+
+		profile := selectedProfile(cmd)
+		if profile == "" {
+			profile = file.CurrentProfile
+		}
+
+		if err := file.Set(profile, args[0], args[1]); err != nil {
+			return err
+		}
+
+		if err := file.Save(path); err != nil {
+			return err
+		}
+
+		if profile == "" {
+			fmt.Printf("✅ Set %s in %s\n", args[0], path)
+		} else {
+			fmt.Printf("✅ Set %s in profile '%s'\n", args[0], profile)
+		}
+		return nil
+	},
+}
+
+var configViewCmd = &cobra.Command{
+	Use:   "view",
+	Short: "Show the effective configuration",
+	Long:  "Show every config key with its effective value and where the value comes from",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := configPath(cmd)
+		showSecrets, _ := cmd.Flags().GetBool("show-secrets")
+
+		if raw, _ := cmd.Flags().GetBool("raw"); raw {
+			file, err := config.Load(path)
+			if err != nil {
+				return err
+			}
+			if !showSecrets {
+				file.MaskSecrets()
+			}
+
+			data, err := yaml.Marshal(file)
+			if err != nil {
+				return fmt.Errorf("failed to encode config: %w", err)
+			}
+			fmt.Print(string(data))
+			return nil
+		}
+
+		resolved, err := resolveConfig(cmd)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Config file: %s\n", path)
+		if resolved.Profile != "" {
+			fmt.Printf("Profile: %s\n", resolved.Profile)
+		} else {
+			fmt.Printf("Profile: (none)\n")
+		}
+		fmt.Println()
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "KEY\tVALUE\tSOURCE")
+		for _, value := range resolved.Values() {
+			shown := value.Value
+			if value.Secret && shown != "" && !showSecrets {
+				shown = config.SecretMask
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", value.Key, shown, value.Source)
+		}
+		return w.Flush()
+	},
+}
+
+var configUseProfileCmd = &cobra.Command{
+	Use:   "use-profile <name>",
+	Short: "Switch the current profile",
+	Args:  cobra.ExactArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		file, err := config.Load(configPath(cmd))
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		return file.ProfileNames(), cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := configPath(cmd)
+
+		file, err := config.Load(path)
+		if err != nil {
+			return err
+		}
+
+		if err := file.UseProfile(args[0]); err != nil {
+			return err
+		}
+
+		if err := file.Save(path); err != nil {
+			return err
+		}
+
+		fmt.Printf("✅ Switched to profile '%s'\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	configViewCmd.Flags().Bool("raw", false, "Print the config file as stored on disk")
+	configViewCmd.Flags().Bool("show-secrets", false, "Print secret values such as tokens and passwords instead of masking them")
+
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configViewCmd)
+	configCmd.AddCommand(configUseProfileCmd)
+	rootCmd.AddCommand(configCmd)
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/crds"
+
+	"github.com/spf13/cobra"
+)
+
+// crdsCmd represents the crds command
+var crdsCmd = &cobra.Command{
+	Use:   "crds",
+	Short: "Manage the DevOpsBeerer CRDs",
+	Long:  "Install, upgrade and inspect the ScenarioDefinition and ActiveScenario CRDs embedded in this CLI",
+}
+
+var crdsInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install missing CRDs",
+	Long:  "Apply the CRDs missing from the cluster with server-side apply and wait until they are established. Existing CRDs are left untouched",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fmt.Println("🍺 Installing DevOpsBeerer CRDs...")
+
+		manager, err := crds.NewManager(cfg)
+		if err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+
+		if err := manager.Install(); err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+
+		return nil
+	},
+}
+
+var crdsUpgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Install or upgrade all CRDs",
+	Long:  "Apply every CRD with server-side apply, taking over fields owned by other managers, and wait until they are established. CRDs newer than this CLI's are only downgraded with --force",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		force, _ := cmd.Flags().GetBool("force")
+		fmt.Println("🍺 Upgrading DevOpsBeerer CRDs...")
+
+		manager, err := crds.NewManager(cfg)
+		if err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+
+		if err := manager.Upgrade(force); err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+
+		return nil
+	},
+}
+
+var crdsStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Compare cluster CRDs with the versions this CLI expects",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := crds.NewManager(cfg)
+		if err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+
+		statuses, err := manager.Status()
+		if err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "CRD\tINSTALLED\tESTABLISHED\tCLUSTER VERSION\tCLI VERSION")
+		for _, status := range statuses {
+			clusterVersion := "-"
+			if status.Installed {
+				clusterVersion = valueOrNone(status.ClusterVersion)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+				status.Name,
+				yesNo(status.Installed),
+				yesNo(status.Established),
+				clusterVersion,
+				status.ExpectedVersion,
+			)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		for _, status := range statuses {
+			if !status.Installed {
+				fmt.Printf("\n⚠️  %s is missing, run: dbeerer crds install\n", status.Name)
+			} else if status.Outdated() {
+				fmt.Printf("\n⚠️  %s is older than this CLI expects, run: dbeerer crds upgrade\n", status.Name)
+			} else if status.Newer() {
+				fmt.Printf("\n⚠️  %s is newer than this CLI knows, upgrade dbeerer\n", status.Name)
+			}
+		}
+
+		return nil
+	},
+}
+
+// warnOutdatedCRDs prints a warning when the cluster's CRDs are missing or older
+// than this CLI expects. Failures are ignored, the command itself reports them
+func warnOutdatedCRDs() {
+	manager, err := crds.NewManager(cfg)
+	if err != nil {
+		return
+	}
+
+	statuses, err := manager.Status()
+	if err != nil {
+		return
+	}
+
+	for _, status := range statuses {
+		if !status.Installed {
+			fmt.Printf("⚠️  CRD %s is missing, run: dbeerer crds install\n", status.Name)
+		} else if status.Outdated() {
+			fmt.Printf("⚠️  CRD %s is older than this CLI expects, run: dbeerer crds upgrade\n", status.Name)
+		}
+	}
+}
+
+// yesNo renders a boolean for tables
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
+
+func init() {
+	crdsUpgradeCmd.Flags().Bool("force", false, "Downgrade CRDs that are newer in the cluster than in this CLI")
+
+	crdsCmd.AddCommand(crdsInstallCmd)
+	crdsCmd.AddCommand(crdsUpgradeCmd)
+	crdsCmd.AddCommand(crdsStatusCmd)
+	rootCmd.AddCommand(crdsCmd)
+}
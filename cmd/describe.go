@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/helm"
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/scenarios"
+	"github.com/spf13/cobra"
+)
+
+// describeCmd represents the describe command
+var describeCmd = &cobra.Command{
+	Use:               "describe <scenario-id>",
+	Short:             "Show details of a scenario",
+	Long:              "Show a scenario's definition, its chart README and values, and its live status when it is running",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeScenarioIDs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		scenarioID := args[0]
+		showChart, _ := cmd.Flags().GetBool("chart")
+
+		manager, err := scenarios.NewManager(cfg)
+		if err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+
+		scenario, err := manager.GetScenario(scenarioID)
+		if err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+
+		fmt.Printf("🍺 %s (%s)\n\n", scenario.Name, scenario.ID)
+		fmt.Printf("%s\n\n", scenario.Description)
+		fmt.Printf("Tags:       %s\n", joinOrNone(scenario.Tags))
+		fmt.Printf("Features:   %s\n", joinOrNone(scenario.Features))
+		fmt.Printf("Chart Link: %s\n", scenario.HelmChart.Link)
+		fmt.Printf("Chart Dir:  %s\n", scenario.HelmChart.Dir)
+		if scenario.HelmChart.Ref != "" {
+			fmt.Printf("Chart Ref:  %s\n", scenario.HelmChart.Ref)
+		}
+		if scenario.HelmChart.Digest != "" {
+			fmt.Printf("Digest:     %s\n", scenario.HelmChart.Digest)
+		}
+
+		// Live status, only when this scenario is running in a slot
+		if statuses, err := manager.ListActiveScenarios(); err == nil {
+			for i := range statuses {
+				if statuses[i].ScenarioID != scenario.ID {
+					continue
+				}
+				fmt.Println()
+				fmt.Println("▶️  Active Scenario Status:")
+				printScenarioStatus(&statuses[i])
+			}
+		}
+
+		if !showChart {
+			return nil
+		}
+
+		fmt.Println()
+		details, err := fetchChartDetails(manager, scenario)
+		if err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+
+		fmt.Println()
+		fmt.Printf("📦 Chart: %s %s", details.Name, details.Version)
+		if details.AppVersion != "" {
+			fmt.Printf(" (app %s)", details.AppVersion)
+		}
+		fmt.Println()
+		if details.Description != "" {
+			fmt.Printf("   %s\n", details.Description)
+		}
+
+		fmt.Println()
+		fmt.Println("⚙️  Values:")
+		if len(details.Values) == 0 {
+			fmt.Println("  (none)")
+		} else {
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "  KEY\tDEFAULT")
+			for _, value := range details.Values {
+				fmt.Fprintf(w, "  %s\t%s\n", value.Key, value.Default)
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+		}
+
+		if details.README != "" {
+			fmt.Println()
+			fmt.Println("📖 README:")
+			fmt.Println()
+			fmt.Println(strings.TrimSpace(details.README))
+		}
+
+		return nil
+	},
+}
+
+// fetchChartDetails downloads a scenario's chart and inspects it
+func fetchChartDetails(manager *scenarios.Manager, scenario *scenarios.Scenario) (*helm.ChartDetails, error) {
+	tempDir, err := os.MkdirTemp("", "dbeerer-chart-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	if _, err := manager.DownloadChart(scenario, tempDir); err != nil {
+		return nil, err
+	}
+
+	return helm.InspectChart(tempDir)
+}
+
+// printScenarioStatus prints the status of an active scenario
+func printScenarioStatus(status *scenarios.ScenarioStatus) {
+	fmt.Printf("  Slot:         %s\n", status.Slot)
+	fmt.Printf("  Mode:         %s\n", status.Mode)
+	fmt.Printf("  Scenario:     %s\n", status.ScenarioID)
+	fmt.Printf("  Phase:        %s\n", valueOrNone(status.Phase))
+	if status.Message != "" {
+		fmt.Printf("  Message:      %s\n", status.Message)
+	}
+	fmt.Printf("  Helm Release: %s\n", valueOrNone(status.HelmRelease))
+	fmt.Printf("  Helm Status:  %s\n", valueOrNone(status.HelmStatus))
+	if status.ChartCommit != "" {
+		fmt.Printf("  Chart Commit: %s\n", status.ChartCommit)
+	}
+	fmt.Printf("  Started:      %s\n", valueOrNone(status.StartTime))
+	if len(status.Values) > 0 {
+		fmt.Printf("  Values:\n")
+		for _, value := range helm.FlattenValues(status.Values) {
+			fmt.Printf("    %s=%s\n", value.Key, value.Default)
+		}
+	}
+}
+
+// joinOrNone joins values with commas, or returns "-" when there are none
+func joinOrNone(values []string) string {
+	if len(values) == 0 {
+		return "-"
+	}
+	return strings.Join(values, ", ")
+}
+
+// valueOrNone returns value, or "-" when it is empty
+func valueOrNone(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+func init() {
+	describeCmd.Flags().Bool("chart", true, "Download the chart to show its README and values")
+	rootCmd.AddCommand(describeCmd)
+}
@@ -28,7 +28,7 @@ var infraDeployCmd = &cobra.Command{
 		fmt.Println()
 
 		// Create infrastructure manager
-		manager := infrastructure.NewManager()
+		manager := infrastructure.NewManager(cfg)
 
 		// Deploy infrastructure
 		if err := manager.DeployInfrastructure(); err != nil {
@@ -51,7 +51,7 @@ var infraStatusCmd = &cobra.Command{
 		fmt.Println("🍺 Checking infrastructure status...")
 
 		// Create infrastructure manager
-		manager := infrastructure.NewManager()
+		manager := infrastructure.NewManager(cfg)
 
 		// Check infrastructure status
 		status, err := manager.CheckInfrastructure()
@@ -1,25 +1,51 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/DevOpsBeerer/dbeerer-cli/internal/scenarios"
+	"github.com/DevOpsBeerer/dbeerer-cli/pkg/apis/devopsbeerer/v1alpha1"
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
 )
 
 // listCmd represents the list command
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List available scenarios",
-	Long:  "List all available scenarios from DevOpsBeerer/playground-scenarios-charts",
-	RunE:  runListCommand,
+	Long: `List all available scenarios from DevOpsBeerer/playground-scenarios-charts.
+Scenarios can be filtered by tag, feature and free text; every given tag and
+feature must match.`,
+	Example: `  dbeerer list --tag pkce --feature refresh-token
+  dbeerer list --search "client credentials"
+  dbeerer list --sort name -o wide --active`,
+	Args: cobra.NoArgs,
+	RunE: runListCommand,
 }
 
 func runListCommand(cmd *cobra.Command, args []string) error {
-	fmt.Println("🔁 Fetching available scenarios...")
+	output, _ := cmd.Flags().GetString("output")
+	if !contains(outputFormats, output) {
+		return fmt.Errorf("unsupported output format '%s' (use %s)", output, strings.Join(outputFormats, ", "))
+	}
+
+	tags, _ := cmd.Flags().GetStringSlice("tag")
+	features, _ := cmd.Flags().GetStringSlice("feature")
+	search, _ := cmd.Flags().GetString("search")
+	sortBy, _ := cmd.Flags().GetString("sort")
+	showActive, _ := cmd.Flags().GetBool("active")
+
+	humanOutput := output == "table" || output == "wide"
+	if humanOutput {
+		fmt.Println("🔁 Fetching available scenarios...")
+	}
 
 	// Create scenario manager
-	manager, err := scenarios.NewManager()
+	manager, err := scenarios.NewManager(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to fetch scenarios: %w", err)
 	}
@@ -29,6 +55,49 @@ func runListCommand(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to fetch scenarios: %w", err)
 	}
 
+	scenarioList = scenarios.FilterScenarios(scenarioList, scenarios.Filter{
+		Tags:     tags,
+		Features: features,
+		Search:   search,
+	})
+
+	if err := scenarios.SortScenarios(scenarioList, sortBy); err != nil {
+		return err
+	}
+
+	// Slots by running scenario ID, an unreachable cluster simply means
+	// nothing is marked
+	activeSlots := map[string]string{}
+	if showActive {
+		if statuses, err := manager.ListActiveScenarios(); err == nil {
+			for _, status := range statuses {
+				activeSlots[status.ScenarioID] = status.Slot
+			}
+		}
+	}
+
+	var encoded interface{} = scenarioList
+	if showActive {
+		encoded = withActive(scenarioList, activeSlots)
+	}
+
+	switch output {
+	case "json":
+		data, err := json.MarshalIndent(encoded, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode scenarios: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	case "yaml":
+		data, err := yaml.Marshal(encoded)
+		if err != nil {
+			return fmt.Errorf("failed to encode scenarios: %w", err)
+		}
+		fmt.Print(string(data))
+		return nil
+	}
+
 	if len(scenarioList) == 0 {
 		fmt.Println("❌ No scenarios found")
 		return nil
@@ -36,16 +105,95 @@ func runListCommand(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("\n🍺 Available Scenarios (%d found):\n\n", len(scenarioList))
 
-	// Display scenarios
-	for _, scenario := range scenarioList {
-		fmt.Printf("  📋 %s (%s)\n", scenario.Name, scenario.ID)
-		fmt.Printf("     %s\n\n", scenario.Description)
+	if output == "wide" {
+		if err := printScenarioTable(scenarioList, activeSlots); err != nil {
+			return err
+		}
+		fmt.Println()
+	} else {
+		// Display scenarios
+		for _, scenario := range scenarioList {
+			marker := ""
+			if slot, ok := activeSlots[scenario.ID]; ok {
+				marker = " ▶️  active"
+				if slot != v1alpha1.DefaultActiveScenarioName {
+					marker += " in " + slot
+				}
+			}
+			fmt.Printf("  📋 %s (%s)%s\n", scenario.Name, scenario.ID, marker)
+			fmt.Printf("     %s\n\n", scenario.Description)
+		}
 	}
 
 	fmt.Println("Usage: dbeerer start <scenario-id>")
 	return nil
 }
 
+// printScenarioTable prints scenarios as a wide table including tags and
+// features, marking the ones running in a slot
+func printScenarioTable(scenarioList []scenarios.Scenario, activeSlots map[string]string) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  \tID\tNAME\tTAGS\tFEATURES\tDESCRIPTION")
+
+	for _, scenario := range scenarioList {
+		marker := ""
+		if _, ok := activeSlots[scenario.ID]; ok {
+			marker = "▶"
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\t%s\n",
+			marker,
+			scenario.ID,
+			scenario.Name,
+			strings.Join(scenario.Tags, ","),
+			strings.Join(scenario.Features, ","),
+			scenario.Description,
+		)
+	}
+
+	return w.Flush()
+}
+
+// listedScenario is a scenario in json and yaml output with --active
+type listedScenario struct {
+	scenarios.Scenario
+	Active bool `json:"active"`
+	// Slot is the slot running the scenario
+	Slot string `json:"slot,omitempty"`
+}
+
+// withActive adds whether each scenario is running, and where
+func withActive(scenarioList []scenarios.Scenario, activeSlots map[string]string) []listedScenario {
+	listed := make([]listedScenario, 0, len(scenarioList))
+	for _, scenario := range scenarioList {
+		slot, ok := activeSlots[scenario.ID]
+		listed = append(listed, listedScenario{Scenario: scenario, Active: ok, Slot: slot})
+	}
+	return listed
+}
+
+// contains reports whether values contains target
+func contains(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
+
 func init() {
+	flags := listCmd.Flags()
+	flags.StringP("output", "o", "table", "Output format: table, wide, json or yaml")
+	flags.StringSlice("tag", nil, "Only show scenarios with this tag (repeatable)")
+	flags.StringSlice("feature", nil, "Only show scenarios with this feature (repeatable)")
+	flags.String("search", "", "Only show scenarios whose ID, name or description contains this text")
+	flags.String("sort", "id", "Sort scenarios by: id or name")
+	flags.Bool("active", false, "Mark the scenarios that are currently running, in every slot")
+
+	_ = listCmd.RegisterFlagCompletionFunc("output", completeOutputFormats)
+	_ = listCmd.RegisterFlagCompletionFunc("tag", completeScenarioTags)
+	_ = listCmd.RegisterFlagCompletionFunc("feature", completeScenarioFeatures)
+	_ = listCmd.RegisterFlagCompletionFunc("sort", cobra.FixedCompletions(scenarios.SortFields, cobra.ShellCompDirectiveNoFileComp))
+
 	rootCmd.AddCommand(listCmd)
 }
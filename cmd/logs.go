@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/scenarios"
+	"github.com/spf13/cobra"
+)
+
+// logsCmd represents the logs command
+var logsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Print the logs of the running scenario",
+	Long:  "Print the logs of every pod of the running scenario, each line prefixed with its pod and container",
+	Example: `  dbeerer logs --tail 50
+  dbeerer logs -f -c beer-app
+  dbeerer logs --name compare`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		slot, _ := cmd.Flags().GetString("name")
+
+		var opts scenarios.LogOptions
+		opts.Follow, _ = cmd.Flags().GetBool("follow")
+		opts.Tail, _ = cmd.Flags().GetInt64("tail")
+		opts.Container, _ = cmd.Flags().GetString("container")
+
+		scenarioManager, err := scenarios.NewManager(cfg)
+		if err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+
+		if err := scenarioManager.StreamLogs(slot, opts, os.Stdout); err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	addSlotFlag(logsCmd)
+	logsCmd.Flags().BoolP("follow", "f", false, "Keep streaming new log lines")
+	logsCmd.Flags().Int64("tail", -1, "Number of recent lines to show per container, negative shows all")
+	logsCmd.Flags().StringP("container", "c", "", "Only show logs of containers with this name")
+
+	rootCmd.AddCommand(logsCmd)
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/scenarios"
+	"github.com/spf13/cobra"
+)
+
+// restartCmd represents the restart command
+var restartCmd = &cobra.Command{
+	Use:   "restart",
+	Short: "Restart the current playground scenario",
+	Long: `Restart the current scenario by rolling its Deployments.
+
+With --hard the scenario is removed, its namespace is waited on until it is
+fully gone, and the scenario is started again with the same mode and values,
+so an exercise can be retried from a clean state.`,
+	Example: `  dbeerer restart
+  dbeerer restart --hard`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		hard, _ := cmd.Flags().GetBool("hard")
+		slot, _ := cmd.Flags().GetString("name")
+
+		scenarioManager, err := scenarios.NewManager(cfg)
+		if err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+
+		if !hard {
+			fmt.Printf("🍺 Restarting current scenario...\n")
+			if err := scenarioManager.RestartScenario(slot); err != nil {
+				return fmt.Errorf("❌ %w", err)
+			}
+			fmt.Printf("✅ Scenario restarted\n")
+			return nil
+		}
+
+		fmt.Printf("🍺 Resetting current scenario...\n")
+		warnOutdatedCRDs()
+
+		if err := scenarioManager.ResetScenario(slot); err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	restartCmd.Flags().Bool("hard", false, "Delete and recreate the scenario from a clean state")
+	addSlotFlag(restartCmd)
+
+	rootCmd.AddCommand(restartCmd)
+}
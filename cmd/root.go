@@ -4,11 +4,30 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/config"
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/httpclient"
+
 	"github.com/spf13/cobra"
 )
 
 var version = "0.1.0"
 
+// cfg holds the effective configuration, resolved before any command runs
+var cfg *config.Config
+
+// configFlags maps global flag names to the config keys they override
+var configFlags = map[string]string{
+	"kubeconfig":               "kubeconfig",
+	"timeout":                  "timeout",
+	"request-timeout":          "timeouts.request",
+	"download-timeout":         "timeouts.download",
+	"install-timeout":          "timeouts.install",
+	"uninstall-timeout":        "timeouts.uninstall",
+	"wait-timeout":             "timeouts.wait",
+	"insecure-skip-tls-verify": "http.insecureSkipVerify",
+	"quiet":                    "quiet",
+}
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "dbeerer",
@@ -16,6 +35,48 @@ var rootCmd = &cobra.Command{
 	Long: `DevOpsBeerer CLI deploys infrastructure and manages OIDC/OAuth2 playground scenarios.
 Scenarios are fetched from DevOpsBeerer/playground-scenarios-charts repository.`,
 	Version: version,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		resolved, err := resolveConfig(cmd)
+		if err != nil {
+			return err
+		}
+		cfg = resolved
+		return nil
+	},
+}
+
+// resolveConfig loads the config file and applies flag overrides
+func resolveConfig(cmd *cobra.Command) (*config.Config, error) {
+	file, err := config.Load(configPath(cmd))
+	if err != nil {
+		return nil, err
+	}
+
+	overrides := map[string]string{}
+	for flagName, key := range configFlags {
+		if flag := cmd.Flags().Lookup(flagName); flag != nil && flag.Changed {
+			overrides[key] = flag.Value.String()
+		}
+	}
+
+	return config.Resolve(file, selectedProfile(cmd), overrides)
+}
+
+// configPath returns the config file selected by --config, DBEERER_CONFIG or the default location
+func configPath(cmd *cobra.Command) string {
+	if path, _ := cmd.Flags().GetString("config"); path != "" {
+		return path
+	}
+	return config.DefaultPath()
+}
+
+// selectedProfile returns the profile selected by --profile or DBEERER_PROFILE
+// An empty result means the config file's current profile
+func selectedProfile(cmd *cobra.Command) string {
+	if profile, _ := cmd.Flags().GetString("profile"); profile != "" {
+		return profile
+	}
+	return os.Getenv(config.EnvPrefix + "PROFILE")
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -26,3 +87,20 @@ func Execute() {
 		os.Exit(1)
 	}
 }
+
+func init() {
+	httpclient.DefaultUserAgent = "dbeerer/" + version
+
+	flags := rootCmd.PersistentFlags()
+	flags.String("config", "", "Path to the config file (default $HOME/.config/dbeerer/config.yaml)")
+	flags.String("profile", "", "Config profile to use (default the current profile)")
+	flags.String("kubeconfig", "", fmt.Sprintf("Path to the kubeconfig file (default %s)", config.DefaultKubeconfig))
+	flags.Duration("timeout", 0, "Timeout applied to every operation unless a specific timeout is set")
+	flags.Duration("request-timeout", 0, fmt.Sprintf("Timeout for a single API request (default %s)", config.DefaultRequestTimeout))
+	flags.Duration("download-timeout", 0, fmt.Sprintf("Timeout for downloading a scenario chart (default %s)", config.DefaultDownloadTimeout))
+	flags.Duration("install-timeout", 0, fmt.Sprintf("Timeout for installing a scenario (default %s)", config.DefaultInstallTimeout))
+	flags.Duration("uninstall-timeout", 0, fmt.Sprintf("Timeout for uninstalling a scenario (default %s)", config.DefaultUninstallTimeout))
+	flags.Duration("wait-timeout", 0, fmt.Sprintf("Timeout for waiting on cluster resources (default %s)", config.DefaultWaitTimeout))
+	flags.BoolP("quiet", "q", false, "Don't list every extracted chart file")
+	flags.Bool("insecure-skip-tls-verify", false, "Skip TLS certificate verification of downloads and git (labs only)")
+}
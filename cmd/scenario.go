@@ -1,9 +1,15 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"path/filepath"
 
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/archive"
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/authoring"
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/helm"
 	"github.com/DevOpsBeerer/dbeerer-cli/internal/scenarios"
+	"github.com/DevOpsBeerer/dbeerer-cli/pkg/apis/devopsbeerer/v1alpha1"
 	"github.com/spf13/cobra"
 )
 
@@ -11,22 +17,50 @@ import (
 var startCmd = &cobra.Command{
 	Use:   "start [scenario-id]",
 	Short: "Start a playground scenario",
-	Long:  "Start a specific scenario by deploying its Helm chart from DevOpsBeerer/playground-scenarios-charts",
-	Args:  cobra.ExactArgs(1),
+	Long: `Start a specific scenario by deploying its Helm chart from DevOpsBeerer/playground-scenarios-charts
+
+By default the in-cluster operator installs the chart once the ActiveScenario
+exists. Use --mode direct on playgrounds without the operator: the CLI then
+downloads the chart and installs it with Helm itself.
+
+Chart values can be overridden like with 'helm install': -f, --set and
+--set-string. The merged values are stored on the ActiveScenario.
+
+With multiScenario enabled, --name starts the scenario in an additional
+slot next to the current one.`,
+	Example: `  dbeerer start oidc-pkce
+  dbeerer start oidc-pkce --mode direct
+  dbeerer start oidc-pkce --set keycloak.accessTokenLifespan=60 -f class-values.yaml
+  dbeerer start scenario-3 --name compare`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeScenarioIDs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		scenarioID := args[0]
-		namespace := scenarioID
+		namespace := cfg.NamespacePrefix + scenarioID
+		mode, _ := cmd.Flags().GetString("mode")
+		slot, _ := cmd.Flags().GetString("name")
+
+		values, err := startValues(cmd)
+		if err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
 
 		fmt.Printf("🍺 Starting scenario: %s\n", scenarioID)
 		fmt.Printf("Namespace: %s\n", namespace)
 
+		warnOutdatedCRDs()
+
 		// Validate scenario exists
-		scenarioManager, err := scenarios.NewManager()
+		scenarioManager, err := scenarios.NewManager(cfg)
 		if err != nil {
 			return fmt.Errorf("❌ %w", err)
 		}
 
-		err = scenarioManager.InstallScenario(scenarioID)
+		err = scenarioManager.InstallScenario(scenarioID, scenarios.InstallOptions{
+			Mode:   mode,
+			Slot:   slot,
+			Values: values,
+		})
 
 		if err != nil {
 			return fmt.Errorf("❌ installing scenario : %w", err)
@@ -36,27 +70,263 @@ var startCmd = &cobra.Command{
 	},
 }
 
+// startValues merges the -f, --set and --set-string flags of cmd
+func startValues(cmd *cobra.Command) (map[string]interface{}, error) {
+	var options helm.ValueOptions
+	options.ValueFiles, _ = cmd.Flags().GetStringSlice("values")
+	options.Values, _ = cmd.Flags().GetStringArray("set")
+	options.StringValues, _ = cmd.Flags().GetStringArray("set-string")
+
+	return options.Merge()
+}
+
 // stopCmd represents the stop command
 var stopCmd = &cobra.Command{
 	Use:   "stop",
 	Short: "Stop the current playground scenario",
-	Long:  "Stop and clean up the current scenario deployment",
+	Long: `Stop and clean up the current scenario deployment
+
+Waits until the ActiveScenario, the scenario's Helm release and its namespace
+are gone, up to the uninstall timeout.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		slot, _ := cmd.Flags().GetString("name")
+
 		fmt.Printf("🍺 Stopping current scenario...\n")
 
 		// Validate scenario exists
-		scenarioManager, err := scenarios.NewManager()
+		scenarioManager, err := scenarios.NewManager(cfg)
+		if err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+
+		err = scenarioManager.UninstallScenario(slot)
+		if errors.Is(err, scenarios.ErrNoActiveScenario) {
+			fmt.Printf("ℹ️  Nothing running\n")
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+
+		fmt.Printf("✅ Scenario stopped\n")
+		return nil
+	},
+}
+
+// scenarioCmd groups the scenario authoring commands
+var scenarioCmd = &cobra.Command{
+	Use:   "scenario",
+	Short: "Manage scenario definitions",
+	Long:  "Register, remove and author ScenarioDefinitions",
+}
+
+var scenarioApplyCmd = &cobra.Command{
+	Use:   "apply -f <file>",
+	Short: "Create or update ScenarioDefinitions from YAML",
+	Long: `Create or update ScenarioDefinitions from YAML files, so a scenario under
+development shows up in 'dbeerer list' and can be started. Files may hold
+several documents; use '-' to read from stdin.`,
+	Example: "  dbeerer scenario apply -f scenario.yaml",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		files, _ := cmd.Flags().GetStringSlice("filename")
+		if len(files) == 0 {
+			return fmt.Errorf("❌ at least one file is required (-f)")
+		}
+
+		// Validate every file before touching the cluster
+		var definitions []*v1alpha1.ScenarioDefinition
+		for _, file := range files {
+			loaded, err := scenarios.LoadDefinitions(file)
+			if err != nil {
+				return fmt.Errorf("❌ %w", err)
+			}
+			definitions = append(definitions, loaded...)
+		}
+
+		scenarioManager, err := scenarios.NewManager(cfg)
+		if err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+
+		for _, definition := range definitions {
+			created, err := scenarioManager.ApplyDefinition(definition)
+			if err != nil {
+				return fmt.Errorf("❌ %w", err)
+			}
+
+			if created {
+				fmt.Printf("✅ Scenario '%s' (%s) created\n", definition.Spec.ID, definition.Spec.Name)
+			} else {
+				fmt.Printf("✅ Scenario '%s' (%s) updated\n", definition.Spec.ID, definition.Spec.Name)
+			}
+		}
+
+		return nil
+	},
+}
+
+var scenarioDeleteCmd = &cobra.Command{
+	Use:               "delete <scenario-id>",
+	Short:             "Delete a ScenarioDefinition",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeScenarioIDs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		scenarioManager, err := scenarios.NewManager(cfg)
+		if err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+
+		if err := scenarioManager.DeleteDefinition(args[0]); err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+
+		fmt.Printf("✅ Scenario '%s' deleted\n", args[0])
+		return nil
+	},
+}
+
+var scenarioInitCmd = &cobra.Command{
+	Use:   "init <scenario-id>",
+	Short: "Generate a new scenario chart skeleton",
+	Long: `Generate a Helm chart skeleton for a new scenario: the beer app deployment,
+an ingress under the playground domain, a Keycloak realm import with the
+app's client, a README template and the matching ScenarioDefinition in
+scenario.yaml. The chart passes 'helm lint' as generated.`,
+	Example: `  dbeerer scenario init oidc-pkce --name "OIDC with PKCE" --tag oidc --feature pkce
+  dbeerer scenario apply -f oidc-pkce/scenario.yaml`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		scenarioID := args[0]
+
+		dir, _ := cmd.Flags().GetString("dir")
+		if dir == "" {
+			dir = scenarioID
+		}
+
+		opts := authoring.ScaffoldOptions{
+			ID:        scenarioID,
+			Domain:    cfg.Domain,
+			ChartLink: cfg.ChartSource,
+		}
+		opts.Name, _ = cmd.Flags().GetString("name")
+		opts.Description, _ = cmd.Flags().GetString("description")
+		opts.Tags, _ = cmd.Flags().GetStringSlice("tag")
+		opts.Features, _ = cmd.Flags().GetStringSlice("feature")
+
+		fmt.Printf("🍺 Generating scenario '%s' in %s\n", scenarioID, dir)
+
+		files, err := authoring.Scaffold(dir, opts)
 		if err != nil {
 			return fmt.Errorf("❌ %w", err)
 		}
 
-		scenarioManager.UninstallScenario()
+		for _, file := range files {
+			fmt.Printf("📄 Created %s\n", file)
+		}
+
+		fmt.Printf("✅ Scenario '%s' generated\n", scenarioID)
+		fmt.Printf("\nNext steps:\n")
+		fmt.Printf("  helm lint %s\n", dir)
+		fmt.Printf("  dbeerer scenario apply -f %s\n", filepath.Join(dir, authoring.DefinitionFile))
+		fmt.Printf("  dbeerer start %s\n", scenarioID)
+		return nil
+	},
+}
+
+var scenarioDigestCmd = &cobra.Command{
+	Use:   "digest <dir>",
+	Short: "Print the digest to pin in a ScenarioDefinition",
+	Long: `Print the SHA-256 digest of a scenario chart directory. Put it in
+spec.helmChart.digest of the ScenarioDefinition, together with a spec.helmChart.ref,
+and installs fail when the downloaded chart doesn't match. scenario.yaml is
+left out so the definition can carry the digest.`,
+	Example: "  dbeerer scenario digest ./oidc-pkce",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		digest, err := archive.DirDigest(args[0], scenarios.DefinitionFile)
+		if err != nil {
+			return fmt.Errorf("❌ failed to compute digest: %w", err)
+		}
+
+		fmt.Println(digest)
+		return nil
+	},
+}
+
+var scenarioLintCmd = &cobra.Command{
+	Use:   "lint <dir>",
+	Short: "Check a scenario chart and its ScenarioDefinition",
+	Long: `Check a scenario before opening a pull request: runs Helm's linter, renders
+the templates and checks the playground conventions (an Ingress under the
+playground domain with cert-manager TLS, no hard-coded namespaces), and
+validates scenario.yaml: its ID must match the directory and its tags and
+features must come from the known vocabulary.`,
+	Example: "  dbeerer scenario lint ./oidc-pkce",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := args[0]
+		strict, _ := cmd.Flags().GetBool("strict")
+
+		fmt.Printf("🔍 Linting scenario in %s\n", dir)
+
+		findings, err := authoring.Lint(dir, authoring.LintOptions{
+			Domain:          cfg.Domain,
+			NamespacePrefix: cfg.NamespacePrefix,
+		})
+		if err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+
+		failures := 0
+		for _, finding := range findings {
+			fmt.Println(finding)
+			if finding.Severity == authoring.SeverityError || strict && finding.Severity == authoring.SeverityWarning {
+				failures++
+			}
+		}
+
+		if failures > 0 {
+			return fmt.Errorf("❌ %d problem(s) found in %s", failures, dir)
+		}
+
+		fmt.Printf("✅ Scenario in %s passed all checks\n", dir)
 		return nil
 	},
 }
 
 func init() {
+	startCmd.Flags().String("mode", scenarios.ModeOperator, "Install mode: operator (installed by the in-cluster operator) or direct (installed by the CLI with Helm)")
+	_ = startCmd.RegisterFlagCompletionFunc("mode", cobra.FixedCompletions(scenarios.Modes, cobra.ShellCompDirectiveNoFileComp))
+	addSlotFlag(startCmd)
+	addSlotFlag(stopCmd)
+	startCmd.Flags().StringSliceP("values", "f", nil, "Values YAML file or URL overriding the chart defaults (repeatable)")
+	startCmd.Flags().StringArray("set", nil, "Set a chart value, e.g. --set key1=val1,key2=val2 (repeatable)")
+	startCmd.Flags().StringArray("set-string", nil, "Set a chart value as a string, e.g. --set-string key=val (repeatable)")
+	_ = startCmd.MarkFlagFilename("values", "yaml", "yml")
+
+	scenarioLintCmd.Flags().Bool("strict", false, "Fail on warnings too")
+
+	scenarioInitCmd.Flags().String("name", "", "Display name of the scenario (default: the scenario ID)")
+	scenarioInitCmd.Flags().String("description", "", "Short description of the scenario")
+	scenarioInitCmd.Flags().StringSlice("tag", nil, "Tag of the scenario from the known vocabulary (repeatable)")
+	scenarioInitCmd.Flags().StringSlice("feature", nil, "Feature the scenario demonstrates from the known vocabulary (repeatable)")
+	scenarioInitCmd.Flags().String("dir", "", "Directory to generate into, named after the scenario ID (default: ./<scenario-id>)")
+	_ = scenarioInitCmd.MarkFlagDirname("dir")
+	_ = scenarioInitCmd.RegisterFlagCompletionFunc("tag", cobra.FixedCompletions(authoring.KnownTags, cobra.ShellCompDirectiveNoFileComp))
+	_ = scenarioInitCmd.RegisterFlagCompletionFunc("feature", cobra.FixedCompletions(authoring.KnownFeatures, cobra.ShellCompDirectiveNoFileComp))
+
+	scenarioApplyCmd.Flags().StringSliceP("filename", "f", nil, "ScenarioDefinition YAML file, or '-' for stdin (repeatable)")
+	_ = scenarioApplyCmd.MarkFlagFilename("filename", "yaml", "yml")
+
+	scenarioCmd.AddCommand(scenarioApplyCmd)
+	scenarioCmd.AddCommand(scenarioDeleteCmd)
+	scenarioCmd.AddCommand(scenarioInitCmd)
+	scenarioCmd.AddCommand(scenarioLintCmd)
+	scenarioCmd.AddCommand(scenarioDigestCmd)
+
 	// Add commands to root
 	rootCmd.AddCommand(startCmd)
 	rootCmd.AddCommand(stopCmd)
+	rootCmd.AddCommand(scenarioCmd)
 }
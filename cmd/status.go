@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/scenarios"
+	"github.com/spf13/cobra"
+)
+
+// statusCmd represents the status command
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the status of the running scenario",
+	Long: `Show the status of the running scenario, or of the scenario in another slot
+with --name. Use --all to list every slot in multi-scenario mode.`,
+	Example: `  dbeerer status
+  dbeerer status --name compare
+  dbeerer status --all`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		slot, _ := cmd.Flags().GetString("name")
+		all, _ := cmd.Flags().GetBool("all")
+
+		if all && slot != "" {
+			return fmt.Errorf("❌ --all and --name cannot be combined")
+		}
+
+		scenarioManager, err := scenarios.NewManager(cfg)
+		if err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+
+		if all {
+			statuses, err := scenarioManager.ListActiveScenarios()
+			if err != nil {
+				return fmt.Errorf("❌ %w", err)
+			}
+			if len(statuses) == 0 {
+				fmt.Printf("ℹ️  Nothing running\n")
+				return nil
+			}
+			return printSlotTable(statuses)
+		}
+
+		status, err := scenarioManager.GetScenarioStatus(slot)
+		if errors.Is(err, scenarios.ErrNoActiveScenario) {
+			fmt.Printf("ℹ️  Nothing running\n")
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+
+		fmt.Println("▶️  Active Scenario Status:")
+		printScenarioStatus(status)
+		return nil
+	},
+}
+
+// printSlotTable prints one line per slot
+func printSlotTable(statuses []scenarios.ScenarioStatus) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SLOT\tSCENARIO\tMODE\tPHASE\tHELM\tSTARTED")
+	for _, status := range statuses {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			status.Slot,
+			status.ScenarioID,
+			status.Mode,
+			valueOrNone(status.Phase),
+			valueOrNone(status.HelmStatus),
+			valueOrNone(status.StartTime),
+		)
+	}
+	return w.Flush()
+}
+
+// addSlotFlag adds the --name flag selecting a scenario slot
+func addSlotFlag(cmd *cobra.Command) {
+	cmd.Flags().String("name", "", "Scenario slot to use (default: the main slot, other slots need multiScenario enabled)")
+	_ = cmd.RegisterFlagCompletionFunc("name", completeSlots)
+}
+
+func init() {
+	addSlotFlag(statusCmd)
+	statusCmd.Flags().Bool("all", false, "List the scenarios of every slot")
+
+	rootCmd.AddCommand(statusCmd)
+}
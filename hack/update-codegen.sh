@@ -0,0 +1,10 @@
+#!/usr/bin/env bash
+# Regenerates the deepcopy functions of the API types
+set -euo pipefail
+
+cd "$(dirname "$0")/.."
+
+go run k8s.io/code-generator/cmd/deepcopy-gen@v0.33.1 \
+  --output-file zz_generated.deepcopy.go \
+  --go-header-file /dev/null \
+  ./pkg/apis/devopsbeerer/v1alpha1
@@ -0,0 +1,78 @@
+package archive
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// DigestPrefix marks the algorithm of a digest string
+const DigestPrefix = "sha256:"
+
+// DirDigest returns the "sha256:<hex>" digest of the regular files below dir
+// It hashes one "<sha256 of content>  <slash path>" line per file, sorted by
+// path, so it depends on content and layout but not on timestamps or modes
+// Files whose relative path is listed in exclude are skipped
+func DirDigest(dir string, exclude ...string) (string, error) {
+	skip := make(map[string]bool, len(exclude))
+	for _, name := range exclude {
+		skip[name] = true
+	}
+
+	type file struct{ path, sum string }
+	var files []file
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.Type().IsRegular() {
+			return err
+		}
+
+		relative, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		relative = filepath.ToSlash(relative)
+		if skip[relative] {
+			return nil
+		}
+
+		sum, err := fileSHA256(path)
+		if err != nil {
+			return err
+		}
+		files = append(files, file{path: relative, sum: sum})
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", dir, err)
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].path < files[j].path
+	})
+
+	hash := sha256.New()
+	for _, f := range files {
+		fmt.Fprintf(hash, "%s  %s\n", f.sum, f.path)
+	}
+	return DigestPrefix + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// fileSHA256 returns the hex SHA-256 of a file's content
+func fileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,238 @@
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Limits bound what a single archive may extract
+type Limits struct {
+	// MaxEntries caps the number of entries read, including skipped ones
+	MaxEntries int
+	// MaxFileSize caps the size of a single extracted file
+	MaxFileSize int64
+	// MaxTotalSize caps the size of all extracted files together
+	MaxTotalSize int64
+}
+
+// DefaultLimits are generous for Helm charts while stopping archive bombs
+var DefaultLimits = Limits{
+	MaxEntries:   50000,
+	MaxFileSize:  20 << 20,
+	MaxTotalSize: 200 << 20,
+}
+
+// Options select which entries ExtractTarGz writes
+type Options struct {
+	// StripComponents drops leading path elements, like tar --strip-components
+	StripComponents int
+	// Dir extracts only the entries below this directory, relative to it
+	// Empty extracts everything
+	Dir string
+	// Limits default to DefaultLimits when zero
+	Limits Limits
+	// OnFile is called with the path of every extracted file relative to dest
+	OnFile func(name string)
+	// OnGlobalHeader is called with the records of pax global headers
+	OnGlobalHeader func(records map[string]string)
+}
+
+// ExtractTarGz extracts a gzipped tarball into dest
+// Entries escaping dest, absolute paths and links are rejected, modes are
+// reduced to 0644/0755, and sizes and entry counts are capped
+func ExtractTarGz(reader io.Reader, dest string, opts Options) error {
+	gzipReader, err := gzip.NewReader(reader)
+	if err != nil {
+		return fmt.Errorf("failed to create gzip reader: %w", err)
+	}
+	defer gzipReader.Close()
+
+	return ExtractTar(gzipReader, dest, opts)
+}
+
+// ExtractTar extracts an uncompressed tarball into dest, see ExtractTarGz
+func ExtractTar(reader io.Reader, dest string, opts Options) error {
+	limits := opts.Limits
+	if limits == (Limits{}) {
+		limits = DefaultLimits
+	}
+
+	dir, err := cleanDir(opts.Dir)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		return fmt.Errorf("failed to create destination directory: %w", err)
+	}
+
+	tarReader := tar.NewReader(reader)
+	entries := 0
+	var total int64
+
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read tar entry: %w", err)
+		}
+
+		entries++
+		if entries > limits.MaxEntries {
+			return fmt.Errorf("archive has more than %d entries", limits.MaxEntries)
+		}
+
+		if header.Typeflag == tar.TypeXGlobalHeader {
+			if opts.OnGlobalHeader != nil {
+				opts.OnGlobalHeader(header.PAXRecords)
+			}
+			continue
+		}
+
+		name, err := entryPath(header.Name, opts.StripComponents, dir)
+		if err != nil {
+			return err
+		}
+		if name == "" {
+			// Outside the selected directory, or the directory itself
+			continue
+		}
+
+		target := filepath.Join(dest, filepath.FromSlash(name))
+		if !within(dest, target) {
+			return fmt.Errorf("archive entry %q escapes the destination", header.Name)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return fmt.Errorf("failed to create directory %s: %w", name, err)
+			}
+
+		case tar.TypeReg:
+			if header.Size > limits.MaxFileSize {
+				return fmt.Errorf("archive entry %q is larger than %d bytes", header.Name, limits.MaxFileSize)
+			}
+			total += header.Size
+			if total > limits.MaxTotalSize {
+				return fmt.Errorf("archive content is larger than %d bytes", limits.MaxTotalSize)
+			}
+
+			if err := extractFile(tarReader, target, header, limits.MaxFileSize); err != nil {
+				return fmt.Errorf("failed to extract file %s: %w", name, err)
+			}
+			if opts.OnFile != nil {
+				opts.OnFile(name)
+			}
+
+		case tar.TypeSymlink, tar.TypeLink:
+			return fmt.Errorf("archive entry %q is a link, links are not supported", header.Name)
+
+		default:
+			// Devices, FIFOs and the like have no place in a chart
+			return fmt.Errorf("archive entry %q has unsupported type %q", header.Name, string(header.Typeflag))
+		}
+	}
+}
+
+// extractFile writes a regular file, reading at most maxSize bytes
+func extractFile(reader io.Reader, target string, header *tar.Header, maxSize int64) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for file: %w", err)
+	}
+
+	// Never follow whatever might already sit at the target path
+	if info, err := os.Lstat(target); err == nil && !info.Mode().IsRegular() {
+		return fmt.Errorf("refusing to overwrite %s", info.Mode().Type())
+	}
+
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, fileMode(header.Mode))
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	// The header size can't be trusted, so bound the copy as well
+	written, err := io.Copy(file, io.LimitReader(reader, maxSize+1))
+	if err != nil {
+		return fmt.Errorf("failed to write file content: %w", err)
+	}
+	if written > maxSize {
+		return fmt.Errorf("file is larger than %d bytes", maxSize)
+	}
+
+	return nil
+}
+
+// entryPath turns a tar entry name into a slash-separated path relative to
+// dir, after stripping leading components
+// It returns "" for entries that are not below dir
+func entryPath(name string, strip int, dir string) (string, error) {
+	if strings.ContainsRune(name, 0) {
+		return "", fmt.Errorf("archive entry %q contains a NUL byte", name)
+	}
+
+	slashed := strings.ReplaceAll(name, "\\", "/")
+	if strings.HasPrefix(slashed, "/") || filepath.IsAbs(name) || filepath.VolumeName(name) != "" {
+		return "", fmt.Errorf("archive entry %q has an absolute path", name)
+	}
+
+	for _, element := range strings.Split(slashed, "/") {
+		if element == ".." {
+			return "", fmt.Errorf("archive entry %q escapes the destination", name)
+		}
+	}
+
+	elements := strings.Split(strings.Trim(path.Clean(slashed), "/"), "/")
+	if len(elements) <= strip {
+		return "", nil
+	}
+	relative := strings.Join(elements[strip:], "/")
+
+	if dir == "" {
+		return relative, nil
+	}
+	if !strings.HasPrefix(relative, dir+"/") {
+		return "", nil
+	}
+	return strings.TrimPrefix(relative, dir+"/"), nil
+}
+
+// cleanDir validates the directory selected in Options
+func cleanDir(dir string) (string, error) {
+	if dir == "" {
+		return "", nil
+	}
+
+	cleaned := strings.Trim(path.Clean("/"+dir), "/")
+	if cleaned != strings.Trim(dir, "/") {
+		return "", fmt.Errorf("invalid directory %q", dir)
+	}
+	return cleaned, nil
+}
+
+// within reports whether target lies inside dest
+func within(dest, target string) bool {
+	relative, err := filepath.Rel(dest, target)
+	if err != nil {
+		return false
+	}
+	return relative != ".." && !strings.HasPrefix(relative, ".."+string(filepath.Separator))
+}
+
+// fileMode drops special bits and keeps only whether a file is executable
+func fileMode(mode int64) os.FileMode {
+	if mode&0111 != 0 {
+		return 0755
+	}
+	return 0644
+}
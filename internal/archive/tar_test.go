@@ -0,0 +1,266 @@
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// entry describes a tar entry to build, regular when typeflag is zero
+type entry struct {
+	name     string
+	body     string
+	mode     int64
+	typeflag byte
+	linkname string
+}
+
+// buildTarGz builds a gzipped tarball in memory
+func buildTarGz(t *testing.T, entries ...entry) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	for _, e := range entries {
+		header := &tar.Header{
+			Name:     e.name,
+			Mode:     e.mode,
+			Typeflag: e.typeflag,
+			Linkname: e.linkname,
+		}
+		if header.Typeflag == 0 {
+			header.Typeflag = tar.TypeReg
+		}
+		if header.Mode == 0 {
+			header.Mode = 0644
+		}
+		if header.Typeflag == tar.TypeReg {
+			header.Size = int64(len(e.body))
+		}
+
+		if err := tarWriter.WriteHeader(header); err != nil {
+			t.Fatalf("failed to write header %q: %v", e.name, err)
+		}
+		if header.Typeflag == tar.TypeReg {
+			if _, err := tarWriter.Write([]byte(e.body)); err != nil {
+				t.Fatalf("failed to write %q: %v", e.name, err)
+			}
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &buf
+}
+
+func TestExtractTarGz(t *testing.T) {
+	dest := t.TempDir()
+	archive := buildTarGz(t,
+		entry{name: "chart/", typeflag: tar.TypeDir, mode: 0755},
+		entry{name: "chart/Chart.yaml", body: "name: demo\n"},
+		entry{name: "chart/templates/cm.yaml", body: "kind: ConfigMap\n"},
+	)
+
+	var files []string
+	err := ExtractTarGz(archive, dest, Options{
+		StripComponents: 1,
+		OnFile:          func(name string) { files = append(files, name) },
+	})
+	if err != nil {
+		t.Fatalf("ExtractTarGz() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dest, "templates", "cm.yaml"))
+	if err != nil || string(data) != "kind: ConfigMap\n" {
+		t.Fatalf("templates/cm.yaml = %q, %v", data, err)
+	}
+	if strings.Join(files, ",") != "Chart.yaml,templates/cm.yaml" {
+		t.Errorf("OnFile got %v", files)
+	}
+}
+
+func TestExtractTarGzDir(t *testing.T) {
+	dest := t.TempDir()
+	archive := buildTarGz(t,
+		entry{name: "repo-main/oidc/Chart.yaml", body: "name: oidc\n"},
+		entry{name: "repo-main/saml/Chart.yaml", body: "name: saml\n"},
+	)
+
+	if err := ExtractTarGz(archive, dest, Options{StripComponents: 1, Dir: "oidc"}); err != nil {
+		t.Fatalf("ExtractTarGz() error = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dest, "Chart.yaml")); err != nil {
+		t.Errorf("Chart.yaml of the selected directory is missing: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "saml")); !os.IsNotExist(err) {
+		t.Errorf("other directory was extracted: %v", err)
+	}
+}
+
+func TestExtractTarGzRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []entry
+		limits  Limits
+		want    string
+	}{
+		{
+			name:    "parent traversal",
+			entries: []entry{{name: "chart/../../evil", body: "x"}},
+			want:    "escapes the destination",
+		},
+		{
+			name:    "traversal after strip",
+			entries: []entry{{name: "../evil", body: "x"}},
+			want:    "escapes the destination",
+		},
+		{
+			name:    "absolute path",
+			entries: []entry{{name: "/etc/evil", body: "x"}},
+			want:    "absolute path",
+		},
+		{
+			name:    "symlink",
+			entries: []entry{{name: "chart/link", typeflag: tar.TypeSymlink, linkname: "values.yaml"}},
+			want:    "links are not supported",
+		},
+		{
+			name:    "symlink outside the destination",
+			entries: []entry{{name: "chart/link", typeflag: tar.TypeSymlink, linkname: "/etc/passwd"}},
+			want:    "links are not supported",
+		},
+		{
+			name:    "hardlink outside the destination",
+			entries: []entry{{name: "chart/link", typeflag: tar.TypeLink, linkname: "../../etc/passwd"}},
+			want:    "links are not supported",
+		},
+		{
+			name:    "character device",
+			entries: []entry{{name: "chart/tty", typeflag: tar.TypeChar}},
+			want:    "unsupported type",
+		},
+		{
+			name:    "block device",
+			entries: []entry{{name: "chart/sda", typeflag: tar.TypeBlock}},
+			want:    "unsupported type",
+		},
+		{
+			name:    "fifo",
+			entries: []entry{{name: "chart/pipe", typeflag: tar.TypeFifo}},
+			want:    "unsupported type",
+		},
+		{
+			name:    "file over the size cap",
+			entries: []entry{{name: "chart/big", body: "0123456789"}},
+			limits:  Limits{MaxEntries: 10, MaxFileSize: 4, MaxTotalSize: 100},
+			want:    "larger than 4 bytes",
+		},
+		{
+			name:    "total over the size cap",
+			entries: []entry{{name: "chart/a", body: "0123"}, {name: "chart/b", body: "4567"}},
+			limits:  Limits{MaxEntries: 10, MaxFileSize: 4, MaxTotalSize: 6},
+			want:    "larger than 6 bytes",
+		},
+		{
+			name:    "too many entries",
+			entries: []entry{{name: "chart/a", body: "a"}, {name: "chart/b", body: "b"}, {name: "chart/c", body: "c"}},
+			limits:  Limits{MaxEntries: 2, MaxFileSize: 100, MaxTotalSize: 100},
+			want:    "more than 2 entries",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dest := t.TempDir()
+			archive := buildTarGz(t, tt.entries...)
+
+			err := ExtractTarGz(archive, dest, Options{Limits: tt.limits})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("ExtractTarGz() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestEntryPathRejectsNUL(t *testing.T) {
+	// archive/tar refuses to write such names, so check the parser directly
+	_, err := entryPath("chart/evil\x00.yaml", 0, "")
+	if err == nil || !strings.Contains(err.Error(), "NUL byte") {
+		t.Fatalf("entryPath() error = %v, want NUL byte error", err)
+	}
+}
+
+func TestExtractTarGzExistingSymlink(t *testing.T) {
+	dest := t.TempDir()
+	outside := filepath.Join(t.TempDir(), "outside")
+	if err := os.WriteFile(outside, []byte("original"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(dest, "values.yaml")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	archive := buildTarGz(t, entry{name: "values.yaml", body: "overwritten"})
+	err := ExtractTarGz(archive, dest, Options{})
+	if err == nil || !strings.Contains(err.Error(), "refusing to overwrite") {
+		t.Fatalf("ExtractTarGz() error = %v, want refusal", err)
+	}
+
+	data, err := os.ReadFile(outside)
+	if err != nil || string(data) != "original" {
+		t.Errorf("symlink target was modified: %q, %v", data, err)
+	}
+}
+
+func TestExtractTarGzNormalizesModes(t *testing.T) {
+	dest := t.TempDir()
+	archive := buildTarGz(t,
+		entry{name: "setuid", body: "x", mode: 04755},
+		entry{name: "setgid", body: "x", mode: 02644},
+		entry{name: "writable", body: "x", mode: 0777},
+		entry{name: "plain", body: "x", mode: 0666},
+	)
+
+	if err := ExtractTarGz(archive, dest, Options{}); err != nil {
+		t.Fatalf("ExtractTarGz() error = %v", err)
+	}
+
+	// The umask may clear bits, but never adds any
+	want := map[string]os.FileMode{
+		"setuid":   0755,
+		"setgid":   0644,
+		"writable": 0755,
+		"plain":    0644,
+	}
+	for name, mode := range want {
+		info, err := os.Stat(filepath.Join(dest, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := info.Mode(); got&^mode != 0 || got&(os.ModeSetuid|os.ModeSetgid|os.ModeSticky) != 0 {
+			t.Errorf("%s mode = %v, want at most %v", name, got, mode)
+		}
+	}
+}
+
+func TestCleanDir(t *testing.T) {
+	for _, dir := range []string{"../oidc", "oidc/../..", "a/./b"} {
+		if _, err := cleanDir(dir); err == nil {
+			t.Errorf("cleanDir(%q) accepted an unclean directory", dir)
+		}
+	}
+	if got, err := cleanDir("oidc/"); err != nil || got != "oidc" {
+		t.Errorf("cleanDir(\"oidc/\") = %q, %v", got, err)
+	}
+}
@@ -0,0 +1,260 @@
+package authoring
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/helm"
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/scenarios"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/lint"
+	"helm.sh/helm/v3/pkg/lint/support"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Severity of a lint finding
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+// String returns the label printed in front of a finding
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "ERROR"
+	case SeverityWarning:
+		return "WARNING"
+	default:
+		return "INFO"
+	}
+}
+
+// certManagerAnnotations are the annotations asking cert-manager for a certificate
+var certManagerAnnotations = []string{
+	"cert-manager.io/cluster-issuer",
+	"cert-manager.io/issuer",
+}
+
+// Finding is a single problem reported by Lint
+type Finding struct {
+	Severity Severity
+	Path     string
+	Message  string
+}
+
+// String formats the finding like 'helm lint' does
+func (f Finding) String() string {
+	return fmt.Sprintf("[%s] %s: %s", f.Severity, f.Path, f.Message)
+}
+
+// LintOptions configures the playground conventions checked by Lint
+type LintOptions struct {
+	// Domain every Ingress host must live under
+	Domain string
+	// NamespacePrefix is prepended to the scenario ID to get the namespace
+	// the chart is rendered into
+	NamespacePrefix string
+}
+
+// Lint checks a scenario directory: Helm's own lint rules, the rendered
+// objects against playground conventions, and the ScenarioDefinition in
+// scenario.yaml
+func Lint(dir string, opts LintOptions) ([]Finding, error) {
+	scenarioID := dirName(dir)
+
+	namespace := opts.NamespacePrefix + scenarioID
+
+	chrt, err := loader.Load(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chart: %w", err)
+	}
+
+	var findings []Finding
+
+	for _, message := range lint.All(dir, nil, namespace, false).Messages {
+		findings = append(findings, Finding{
+			Severity: severityFromHelm(message.Severity),
+			Path:     message.Path,
+			Message:  message.Err.Error(),
+		})
+	}
+
+	// Render with the release name and namespace 'dbeerer start' would use
+	objects, err := helm.RenderChart(chrt, scenarios.HelmReleaseName(scenarioID), namespace, nil)
+	if err != nil {
+		// Helm's linter already reports template errors in detail
+		findings = append(findings, Finding{Severity: SeverityError, Path: "templates/", Message: err.Error()})
+	} else {
+		findings = append(findings, lintObjects(objects, namespace, opts.Domain)...)
+	}
+
+	findings = append(findings, lintDefinition(dir, scenarioID)...)
+
+	return findings, nil
+}
+
+// lintObjects checks the rendered objects against the playground conventions
+func lintObjects(objects []helm.RenderedObject, namespace, domain string) []Finding {
+	var findings []Finding
+	ingresses := 0
+
+	for _, rendered := range objects {
+		object := rendered.Object
+		path := templatePath(rendered.Template)
+
+		// The namespace is chosen by the CLI, charts must not pin one
+		if pinned := object.GetNamespace(); pinned != "" && pinned != namespace {
+			findings = append(findings, Finding{
+				Severity: SeverityError,
+				Path:     path,
+				Message:  fmt.Sprintf("%s %s hard-codes namespace %q, use {{ .Release.Namespace }} or leave it unset", object.GetKind(), object.GetName(), pinned),
+			})
+		}
+
+		if object.GetKind() == "Ingress" {
+			ingresses++
+			findings = append(findings, lintIngress(object, path, domain)...)
+		}
+	}
+
+	if ingresses == 0 {
+		findings = append(findings, Finding{
+			Severity: SeverityError,
+			Path:     "templates/",
+			Message:  fmt.Sprintf("no Ingress found, scenarios must be reachable under %s", domain),
+		})
+	}
+
+	return findings
+}
+
+// lintIngress checks an Ingress serves hosts under the playground domain
+// over TLS issued by cert-manager
+func lintIngress(ingress *unstructured.Unstructured, path, domain string) []Finding {
+	var findings []Finding
+	report := func(format string, args ...interface{}) {
+		findings = append(findings, Finding{
+			Severity: SeverityError,
+			Path:     path,
+			Message:  fmt.Sprintf("Ingress %s ", ingress.GetName()) + fmt.Sprintf(format, args...),
+		})
+	}
+
+	rules, _, _ := unstructured.NestedSlice(ingress.Object, "spec", "rules")
+	if len(rules) == 0 {
+		report("has no rules")
+	}
+	for _, item := range rules {
+		rule, _ := item.(map[string]interface{})
+		host, _ := rule["host"].(string)
+		if host == "" {
+			report("has a rule without host")
+		} else if !underDomain(host, domain) {
+			report("host %q is not under the playground domain %s", host, domain)
+		}
+	}
+
+	tls, _, _ := unstructured.NestedSlice(ingress.Object, "spec", "tls")
+	if len(tls) == 0 {
+		report("has no tls section")
+	}
+	for _, item := range tls {
+		entry, _ := item.(map[string]interface{})
+		if secretName, _ := entry["secretName"].(string); secretName == "" {
+			report("has a tls entry without secretName")
+		}
+	}
+
+	annotations := ingress.GetAnnotations()
+	hasIssuer := false
+	for _, annotation := range certManagerAnnotations {
+		if annotations[annotation] != "" {
+			hasIssuer = true
+		}
+	}
+	if !hasIssuer {
+		report("is missing a cert-manager issuer annotation (%s)", strings.Join(certManagerAnnotations, " or "))
+	}
+
+	return findings
+}
+
+// lintDefinition checks the ScenarioDefinition shipped next to the chart
+func lintDefinition(dir, scenarioID string) []Finding {
+	var findings []Finding
+	report := func(severity Severity, format string, args ...interface{}) {
+		findings = append(findings, Finding{Severity: severity, Path: DefinitionFile, Message: fmt.Sprintf(format, args...)})
+	}
+
+	definitionPath := filepath.Join(dir, DefinitionFile)
+	if _, err := os.Stat(definitionPath); errors.Is(err, fs.ErrNotExist) {
+		report(SeverityError, "file not found, every scenario ships its ScenarioDefinition")
+		return findings
+	}
+
+	definitions, err := scenarios.LoadDefinitions(definitionPath)
+	if err != nil {
+		report(SeverityError, "%v", err)
+		return findings
+	}
+	if len(definitions) > 1 {
+		report(SeverityError, "holds %d documents, expected a single ScenarioDefinition", len(definitions))
+	}
+
+	spec := definitions[0].Spec
+	if spec.ID != scenarioID {
+		report(SeverityError, "spec.id %q does not match the directory %q", spec.ID, scenarioID)
+	}
+	if spec.HelmChart.Dir != "" && spec.HelmChart.Dir != scenarioID {
+		report(SeverityError, "spec.helmChart.dir %q does not match the directory %q", spec.HelmChart.Dir, scenarioID)
+	}
+	if strings.TrimSpace(spec.Description) == "" {
+		report(SeverityWarning, "spec.description is empty")
+	}
+
+	for _, tag := range spec.Tags {
+		if !isKnown(KnownTags, tag) {
+			report(SeverityError, "unknown tag %q, known tags: %s", tag, strings.Join(KnownTags, ", "))
+		}
+	}
+	for _, feature := range spec.Features {
+		if !isKnown(KnownFeatures, feature) {
+			report(SeverityError, "unknown feature %q, known features: %s", feature, strings.Join(KnownFeatures, ", "))
+		}
+	}
+
+	return findings
+}
+
+// underDomain reports whether host is domain or one of its subdomains
+func underDomain(host, domain string) bool {
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// templatePath strips the chart name from a rendered template name
+func templatePath(name string) string {
+	if _, rest, found := strings.Cut(name, "/"); found {
+		return rest
+	}
+	return name
+}
+
+// severityFromHelm maps Helm's lint severities onto ours
+func severityFromHelm(severity int) Severity {
+	switch severity {
+	case support.ErrorSev:
+		return SeverityError
+	case support.WarningSev:
+		return SeverityWarning
+	default:
+		return SeverityInfo
+	}
+}
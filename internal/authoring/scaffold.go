@@ -0,0 +1,136 @@
+package authoring
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/scenarios"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// DefinitionFile is the ScenarioDefinition written next to the chart
+const DefinitionFile = scenarios.DefinitionFile
+
+// templatesRoot holds the scaffold, which uses [[ ]] delimiters so the
+// Helm templates inside pass through untouched
+const templatesRoot = "templates/chart"
+
+//go:embed all:templates
+var templates embed.FS
+
+// ScaffoldOptions describes the scenario to generate
+type ScaffoldOptions struct {
+	ID          string
+	Name        string
+	Description string
+	Tags        []string
+	Features    []string
+	Domain      string
+	ChartLink   string
+}
+
+// Scaffold writes a new scenario chart and its ScenarioDefinition into dir
+// and returns the generated files relative to dir
+// It refuses to write into a non-empty directory, and anything Lint would
+// reject: a directory not named after the ID, unknown tags or features
+func Scaffold(dir string, opts ScaffoldOptions) ([]string, error) {
+	if errs := validation.IsDNS1123Label(opts.ID); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid scenario ID %q: %s", opts.ID, strings.Join(errs, ", "))
+	}
+	if name := dirName(dir); name != opts.ID {
+		return nil, fmt.Errorf("directory %s must be named after the scenario ID %q, charts are looked up by it", dir, opts.ID)
+	}
+	for _, tag := range opts.Tags {
+		if !isKnown(KnownTags, tag) {
+			return nil, fmt.Errorf("unknown tag %q, known tags: %s", tag, strings.Join(KnownTags, ", "))
+		}
+	}
+	for _, feature := range opts.Features {
+		if !isKnown(KnownFeatures, feature) {
+			return nil, fmt.Errorf("unknown feature %q, known features: %s", feature, strings.Join(KnownFeatures, ", "))
+		}
+	}
+	if opts.Name == "" {
+		opts.Name = opts.ID
+	}
+	if opts.Description == "" {
+		opts.Description = fmt.Sprintf("TODO: describe the %s scenario", opts.Name)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	if len(entries) > 0 {
+		return nil, fmt.Errorf("directory %s is not empty", dir)
+	}
+
+	var files []string
+	err = fs.WalkDir(templates, templatesRoot, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		relative := strings.TrimSuffix(strings.TrimPrefix(name, templatesRoot+"/"), ".tmpl")
+		content, err := renderTemplate(name, opts)
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dir, filepath.FromSlash(relative))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+		if err := os.WriteFile(target, content, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", relative, err)
+		}
+
+		files = append(files, relative)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// dirName returns the name of a directory the way Lint derives the scenario
+// ID from it
+func dirName(dir string) string {
+	if abs, err := filepath.Abs(dir); err == nil {
+		return filepath.Base(abs)
+	}
+	return filepath.Base(filepath.Clean(dir))
+}
+
+// renderTemplate executes a single embedded scaffold template
+func renderTemplate(name string, opts ScaffoldOptions) ([]byte, error) {
+	data, err := templates.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template %s: %w", name, err)
+	}
+
+	tmpl, err := template.New(path.Base(name)).
+		Delims("[[", "]]").
+		Funcs(template.FuncMap{"quote": strconv.Quote}).
+		Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, opts); err != nil {
+		return nil, fmt.Errorf("failed to render template %s: %w", name, err)
+	}
+	return buf.Bytes(), nil
+}
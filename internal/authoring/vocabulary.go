@@ -0,0 +1,53 @@
+package authoring
+
+// KnownTags is the vocabulary scenario tags are picked from, so filtering
+// with 'dbeerer list --tag' finds scenarios consistently
+var KnownTags = []string{
+	"oidc",
+	"oauth2",
+	"saml",
+	"sso",
+	"jwt",
+	"pkce",
+	"keycloak",
+	"spa",
+	"api",
+	"backend",
+	"mobile",
+	"beginner",
+	"intermediate",
+	"advanced",
+}
+
+// KnownFeatures is the vocabulary of protocol features a scenario demonstrates
+var KnownFeatures = []string{
+	"authorization-code",
+	"client-credentials",
+	"device-code",
+	"implicit",
+	"password",
+	"pkce",
+	"refresh-token",
+	"token-exchange",
+	"token-introspection",
+	"token-revocation",
+	"userinfo",
+	"id-token",
+	"logout",
+	"single-logout",
+	"sso",
+	"mfa",
+	"consent",
+	"rbac",
+	"service-account",
+}
+
+// isKnown reports whether value is part of vocabulary
+func isKnown(vocabulary []string, value string) bool {
+	for _, known := range vocabulary {
+		if known == value {
+			return true
+		}
+	}
+	return false
+}
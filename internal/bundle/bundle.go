@@ -0,0 +1,369 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/cache"
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/config"
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/helm"
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/scenarios"
+	"github.com/DevOpsBeerer/dbeerer-cli/pkg/apis/devopsbeerer/v1alpha1"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// Version of the bundle layout written by Create
+const Version = 1
+
+// Files inside a bundle
+const (
+	ManifestFile     = "bundle.json"
+	DefinitionsDir   = "definitions"
+	ChartsDir        = "charts"
+	ImageListFile    = "images.txt"
+	ImageArchiveFile = "images.tar"
+)
+
+// Manifest describes the content of a bundle
+type Manifest struct {
+	Version   int               `json:"version"`
+	CreatedAt time.Time         `json:"createdAt"`
+	Scenarios []BundledScenario `json:"scenarios"`
+	// Images lists every image referenced by the bundled charts
+	Images []string `json:"images"`
+	// ImagesIncluded is set when ImageArchiveFile holds the images
+	ImagesIncluded bool `json:"imagesIncluded"`
+}
+
+// BundledScenario records where a bundled scenario's chart came from
+type BundledScenario struct {
+	ID string `json:"id"`
+	// Repo and Ref key the chart repository tarball in the chart cache
+	Repo string `json:"repo"`
+	Ref  string `json:"ref"`
+	// Tarball is the chart repository tarball inside ChartsDir
+	Tarball string   `json:"tarball"`
+	Images  []string `json:"images"`
+}
+
+// CreateOptions configures a new bundle
+type CreateOptions struct {
+	// Scenarios are the IDs of the scenarios to bundle
+	Scenarios []string
+	// Output is the path of the bundle to write
+	Output string
+	// IncludeImages pulls the images into the bundle as an OCI archive
+	IncludeImages bool
+	// Platform selects the image variant to pull, e.g. linux/amd64
+	Platform string
+}
+
+// Manager creates and imports offline scenario bundles
+type Manager struct {
+	scenarios *scenarios.Manager
+	cache     *cache.Cache
+	config    *config.Config
+}
+
+// NewManager creates a new bundle manager
+func NewManager(cfg *config.Config) (*Manager, error) {
+	scenarioManager, err := scenarios.NewManager(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Manager{
+		scenarios: scenarioManager,
+		cache:     cache.New(),
+		config:    cfg,
+	}, nil
+}
+
+// Create writes a bundle with the charts, ScenarioDefinitions and images
+// of the given scenarios
+// Bundled definitions are pinned to the commit their chart was downloaded
+// from, so imported scenarios start from the chart cache
+func (m *Manager) Create(opts CreateOptions) error {
+	if len(opts.Scenarios) == 0 {
+		return fmt.Errorf("no scenarios to bundle")
+	}
+
+	workDir, err := os.MkdirTemp("", "dbeerer-bundle-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	manifest := Manifest{
+		Version:   Version,
+		CreatedAt: time.Now().UTC(),
+	}
+	definitions := map[string][]byte{}
+	tarballs := map[string]*cache.Entry{}
+	images := map[string]bool{}
+
+	for _, scenarioID := range opts.Scenarios {
+		fmt.Printf("📦 Bundling scenario '%s'...\n", scenarioID)
+
+		bundled, definition, entry, err := m.bundleScenario(scenarioID, workDir)
+		if err != nil {
+			return fmt.Errorf("failed to bundle %s: %w", scenarioID, err)
+		}
+
+		manifest.Scenarios = append(manifest.Scenarios, *bundled)
+		definitions[scenarioID] = definition
+		tarballs[bundled.Tarball] = entry
+		for _, image := range bundled.Images {
+			images[image] = true
+		}
+	}
+
+	for image := range images {
+		manifest.Images = append(manifest.Images, image)
+	}
+	sort.Strings(manifest.Images)
+
+	imageArchive := ""
+	if opts.IncludeImages && len(manifest.Images) > 0 {
+		imageArchive = filepath.Join(workDir, ImageArchiveFile)
+		if err := pullImages(m.config, manifest.Images, opts.Platform, imageArchive); err != nil {
+			return err
+		}
+		manifest.ImagesIncluded = true
+	}
+
+	return writeBundle(opts.Output, &manifest, definitions, tarballs, m.cache, imageArchive)
+}
+
+// bundleScenario downloads a scenario's chart, collects the images its
+// templates reference and returns its pinned definition as YAML
+func (m *Manager) bundleScenario(scenarioID, workDir string) (*BundledScenario, []byte, *cache.Entry, error) {
+	definition, err := m.scenarios.GetDefinition(scenarioID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	scenario, err := m.scenarios.GetScenario(scenarioID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	chartPath := filepath.Join(workDir, scenarioID)
+	download, err := m.scenarios.DownloadChart(scenario, chartPath)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if download.Repo == "" {
+		return nil, nil, nil, fmt.Errorf("chart of %s comes from a local directory, there is nothing to bundle", scenarioID)
+	}
+	entry, err := m.cache.Lookup(download.Repo, download.Ref)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if entry == nil {
+		return nil, nil, nil, fmt.Errorf("chart repository %s@%s missing from the chart cache", download.Repo, download.Ref)
+	}
+
+	chrt, err := loader.Load(chartPath)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to load chart: %w", err)
+	}
+	objects, err := helm.RenderChart(chrt, scenarios.HelmReleaseName(scenarioID), m.config.NamespacePrefix+scenarioID, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	ref := download.Pin()
+
+	bundled := &BundledScenario{
+		ID:      scenarioID,
+		Repo:    download.Repo,
+		Ref:     ref,
+		Tarball: entry.Digest + ".tar.gz",
+		Images:  imagesFromObjects(objects),
+	}
+	fmt.Printf("🖼️  %d image(s) referenced by %s\n", len(bundled.Images), scenarioID)
+
+	data, err := definitionYAML(definition, ref)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return bundled, data, entry, nil
+}
+
+// definitionYAML returns a ScenarioDefinition pinned to ref, without the
+// fields the API server sets
+func definitionYAML(definition *v1alpha1.ScenarioDefinition, ref string) ([]byte, error) {
+	pinned := &v1alpha1.ScenarioDefinition{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "ScenarioDefinition",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        definition.Name,
+			Labels:      definition.Labels,
+			Annotations: definition.Annotations,
+		},
+		Spec: *definition.Spec.DeepCopy(),
+	}
+	pinned.Spec.HelmChart.Ref = ref
+
+	data, err := yaml.Marshal(pinned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode scenario definition: %w", err)
+	}
+	return data, nil
+}
+
+// writeBundle writes the bundle tarball to output
+func writeBundle(output string, manifest *Manifest, definitions map[string][]byte, tarballs map[string]*cache.Entry, chartCache *cache.Cache, imageArchive string) error {
+	file, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("failed to create bundle: %w", err)
+	}
+	defer file.Close()
+
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := addBytes(tarWriter, ManifestFile, manifestData); err != nil {
+		return err
+	}
+
+	for _, bundled := range manifest.Scenarios {
+		if err := addBytes(tarWriter, DefinitionsDir+"/"+bundled.ID+".yaml", definitions[bundled.ID]); err != nil {
+			return err
+		}
+	}
+
+	// Scenarios sharing a chart repository share its tarball
+	names := make([]string, 0, len(tarballs))
+	for name := range tarballs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		entry := tarballs[name]
+		blob, err := chartCache.OpenBlob(entry)
+		if err != nil {
+			return err
+		}
+		err = addReader(tarWriter, ChartsDir+"/"+name, entry.Size, blob)
+		blob.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	imageList := strings.Join(manifest.Images, "\n")
+	if imageList != "" {
+		imageList += "\n"
+	}
+	if err := addBytes(tarWriter, ImageListFile, []byte(imageList)); err != nil {
+		return err
+	}
+
+	if imageArchive != "" {
+		if err := addFile(tarWriter, ImageArchiveFile, imageArchive); err != nil {
+			return err
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	fmt.Printf("✅ Bundle written to %s (%d scenario(s), %d image(s))\n", output, len(manifest.Scenarios), len(manifest.Images))
+	return nil
+}
+
+// addBytes adds a regular file holding data to the tarball
+func addBytes(tarWriter *tar.Writer, name string, data []byte) error {
+	return addReader(tarWriter, name, int64(len(data)), bytes.NewReader(data))
+}
+
+// addFile adds the file at path to the tarball
+func addFile(tarWriter *tar.Writer, name, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+
+	return addReader(tarWriter, name, info.Size(), file)
+}
+
+// writeTar packs the files below dir into an uncompressed tarball at output
+func writeTar(dir, output string) error {
+	file, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", output, err)
+	}
+	defer file.Close()
+
+	tarWriter := tar.NewWriter(file)
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		return addFile(tarWriter, filepath.ToSlash(name), path)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+	return file.Close()
+}
+
+// addReader adds a regular file of the given size read from reader
+func addReader(tarWriter *tar.Writer, name string, size int64, reader io.Reader) error {
+	header := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     size,
+		Mode:     0644,
+		ModTime:  time.Now(),
+	}
+	if err := tarWriter.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write %s to bundle: %w", name, err)
+	}
+	if _, err := io.Copy(tarWriter, reader); err != nil {
+		return fmt.Errorf("failed to write %s to bundle: %w", name, err)
+	}
+	return nil
+}
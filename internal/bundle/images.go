@@ -0,0 +1,219 @@
+package bundle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/config"
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/helm"
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/httpclient"
+	"github.com/distribution/reference"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content/oci"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/credentials"
+)
+
+// containerdImageName is the annotation 'ctr images import' names images by
+const containerdImageName = "io.containerd.image.name"
+
+// containerFields hold the containers of a pod spec
+var containerFields = map[string]bool{
+	"containers":          true,
+	"initContainers":      true,
+	"ephemeralContainers": true,
+}
+
+// imagesFromObjects returns the images of every container in the rendered
+// objects, wherever their pod spec is nested
+func imagesFromObjects(objects []helm.RenderedObject) []string {
+	found := map[string]bool{}
+	for _, rendered := range objects {
+		collectImages(rendered.Object.Object, found)
+	}
+
+	images := make([]string, 0, len(found))
+	for image := range found {
+		images = append(images, image)
+	}
+	sort.Strings(images)
+	return images
+}
+
+// collectImages walks an object looking for container lists
+func collectImages(value interface{}, found map[string]bool) {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, child := range typed {
+			if containers, ok := child.([]interface{}); ok && containerFields[key] {
+				for _, item := range containers {
+					container, _ := item.(map[string]interface{})
+					if image, _ := container["image"].(string); image != "" {
+						found[image] = true
+					}
+				}
+				continue
+			}
+			collectImages(child, found)
+		}
+	case []interface{}:
+		for _, child := range typed {
+			collectImages(child, found)
+		}
+	}
+}
+
+// pullImages copies images from their registries into an OCI archive
+// Only the manifest matching platform ("os/arch") is kept
+func pullImages(cfg *config.Config, images []string, platform, archivePath string) error {
+	target, err := parsePlatform(platform)
+	if err != nil {
+		return err
+	}
+
+	client, err := registryClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	layoutDir := archivePath + ".layout"
+	store, err := oci.New(layoutDir)
+	if err != nil {
+		return fmt.Errorf("failed to create OCI layout: %w", err)
+	}
+	defer os.RemoveAll(layoutDir)
+
+	for _, image := range images {
+		fmt.Printf("📥 Pulling image %s (%s)\n", image, platform)
+		if err := pullImage(cfg, client, store, image, target); err != nil {
+			return fmt.Errorf("failed to pull %s: %w", image, err)
+		}
+	}
+
+	if err := nameImages(layoutDir); err != nil {
+		return err
+	}
+
+	return writeTar(layoutDir, archivePath)
+}
+
+// registryClient returns the client images are pulled with, logged in with
+// the Docker credential store
+func registryClient(cfg *config.Config) (*auth.Client, error) {
+	httpClient, err := httpclient.New(cfg, cfg.Timeouts.Download)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := credentials.NewStoreFromDocker(credentials.StoreOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load Docker credentials: %w", err)
+	}
+
+	return &auth.Client{
+		Client:     httpClient,
+		Credential: credentials.Credential(store),
+		Cache:      auth.NewCache(),
+	}, nil
+}
+
+// pullImage copies a single image into the OCI store, tagged with its
+// fully qualified reference
+func pullImage(cfg *config.Config, client *auth.Client, store *oci.Store, image string, platform *ocispec.Platform) error {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return err
+	}
+	named = reference.TagNameOnly(named)
+
+	repository, err := remote.NewRepository(named.Name())
+	if err != nil {
+		return err
+	}
+	repository.Client = client
+	repository.PlainHTTP = cfg.Registry.PlainHTTP
+
+	srcRef := ""
+	switch ref := named.(type) {
+	case reference.Digested:
+		srcRef = ref.Digest().String()
+	case reference.Tagged:
+		srcRef = ref.Tag()
+	}
+
+	opts := oras.DefaultCopyOptions
+	opts.WithTargetPlatform(platform)
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeouts.Download)
+	defer cancel()
+
+	_, err = oras.Copy(ctx, repository, srcRef, store, named.String(), opts)
+	return err
+}
+
+// nameImages adds the annotation containerd names imported images by to
+// every manifest of the OCI layout's index
+func nameImages(layoutDir string) error {
+	indexPath := filepath.Join(layoutDir, "index.json")
+	data, err := os.ReadFile(indexPath)
+	if err != nil {
+		return fmt.Errorf("failed to read OCI index: %w", err)
+	}
+
+	var index ocispec.Index
+	if err := json.Unmarshal(data, &index); err != nil {
+		return fmt.Errorf("failed to parse OCI index: %w", err)
+	}
+
+	var manifests []ocispec.Descriptor
+	for _, manifest := range index.Manifests {
+		// Manifests only referenced by digest have no name to import under
+		name := manifest.Annotations[ocispec.AnnotationRefName]
+		if name == "" {
+			continue
+		}
+		manifest.Annotations[containerdImageName] = name
+		manifests = append(manifests, manifest)
+	}
+	index.Manifests = manifests
+
+	data, err = json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(indexPath, data, 0644)
+}
+
+// parsePlatform parses "os/arch" or "os/arch/variant"
+func parsePlatform(platform string) (*ocispec.Platform, error) {
+	parts := strings.Split(platform, "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid platform '%s', expected os/arch", platform)
+	}
+
+	target := &ocispec.Platform{OS: parts[0], Architecture: parts[1]}
+	if len(parts) == 3 {
+		target.Variant = parts[2]
+	}
+	return target, nil
+}
+
+// importImages loads an OCI archive into the K3s containerd
+func importImages(archivePath string) error {
+	cmd := exec.Command("k3s", "ctr", "images", "import", archivePath)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("k3s image import failed (run as root, or copy the archive to /var/lib/rancher/k3s/agent/images/ and restart K3s): %w", err)
+	}
+	return nil
+}
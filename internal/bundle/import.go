@@ -0,0 +1,136 @@
+package bundle
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/archive"
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/scenarios"
+)
+
+// importLimits allow the image archives of a whole class while still
+// bounding what a bundle may unpack
+var importLimits = archive.Limits{
+	MaxEntries:   10000,
+	MaxFileSize:  16 << 30,
+	MaxTotalSize: 32 << 30,
+}
+
+// ImportOptions configures Import
+type ImportOptions struct {
+	// SkipImages leaves the bundled images out, e.g. when they were
+	// imported already
+	SkipImages bool
+}
+
+// Import loads a bundle: the chart repositories go into the chart cache,
+// the ScenarioDefinitions are applied and the images imported into K3s
+func (m *Manager) Import(path string, opts ImportOptions) error {
+	workDir, err := os.MkdirTemp("", "dbeerer-bundle-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open bundle: %w", err)
+	}
+	defer file.Close()
+
+	fmt.Printf("📦 Unpacking %s...\n", path)
+	if err := archive.ExtractTarGz(file, workDir, archive.Options{Limits: importLimits}); err != nil {
+		return fmt.Errorf("failed to unpack bundle: %w", err)
+	}
+
+	manifest, err := readManifest(workDir)
+	if err != nil {
+		return err
+	}
+
+	for _, bundled := range manifest.Scenarios {
+		if err := m.importChart(workDir, bundled); err != nil {
+			return fmt.Errorf("failed to import chart of %s: %w", bundled.ID, err)
+		}
+	}
+
+	for _, bundled := range manifest.Scenarios {
+		if err := m.importDefinition(workDir, bundled.ID); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case len(manifest.Images) == 0:
+	case !manifest.ImagesIncluded:
+		fmt.Printf("⚠️  The bundle lists %d image(s) but doesn't include them, the cluster must be able to pull them\n", len(manifest.Images))
+	case opts.SkipImages:
+		fmt.Printf("ℹ️  Skipping %d image(s)\n", len(manifest.Images))
+	default:
+		fmt.Printf("🖼️  Importing %d image(s) into K3s...\n", len(manifest.Images))
+		if err := importImages(filepath.Join(workDir, ImageArchiveFile)); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("✅ Imported %d scenario(s)\n", len(manifest.Scenarios))
+	return nil
+}
+
+// readManifest reads and checks the manifest of an unpacked bundle
+func readManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("not a scenario bundle: %w", err)
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", ManifestFile, err)
+	}
+	if manifest.Version != Version {
+		return nil, fmt.Errorf("unsupported bundle version %d, this CLI reads version %d", manifest.Version, Version)
+	}
+
+	return &manifest, nil
+}
+
+// importChart stores a bundled chart repository in the chart cache under
+// the ref the bundled definition is pinned to
+func (m *Manager) importChart(dir string, bundled BundledScenario) error {
+	tarball, err := os.Open(filepath.Join(dir, ChartsDir, filepath.Base(bundled.Tarball)))
+	if err != nil {
+		return err
+	}
+	defer tarball.Close()
+
+	if _, err := m.cache.Store(bundled.Repo, bundled.Ref, "", tarball); err != nil {
+		return err
+	}
+
+	fmt.Printf("📦 Cached %s@%s\n", bundled.Repo, bundled.Ref)
+	return nil
+}
+
+// importDefinition applies a bundled ScenarioDefinition
+func (m *Manager) importDefinition(dir, scenarioID string) error {
+	definitions, err := scenarios.LoadDefinitions(filepath.Join(dir, DefinitionsDir, scenarioID+".yaml"))
+	if err != nil {
+		return err
+	}
+
+	for _, definition := range definitions {
+		created, err := m.scenarios.ApplyDefinition(definition)
+		if err != nil {
+			return err
+		}
+		if created {
+			fmt.Printf("✅ Scenario '%s' created\n", definition.Spec.ID)
+		} else {
+			fmt.Printf("✅ Scenario '%s' updated\n", definition.Spec.ID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,304 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Cache stores downloaded chart repository tarballs on disk
+//
+// Tarballs are content-addressed: blobs/sha256/<hex>.tar.gz holds the
+// bytes, and entries/<key>.json maps a repository and ref onto a blob
+// together with the ETag needed to revalidate it
+type Cache struct {
+	dir string
+}
+
+// Entry describes a cached repository tarball
+type Entry struct {
+	Repo string `json:"repo"`
+	Ref  string `json:"ref"`
+	// ETag identifies the server's version of the tarball, the manifest
+	// digest for charts pulled from OCI registries
+	ETag      string    `json:"etag,omitempty"`
+	Digest    string    `json:"digest"`
+	Size      int64     `json:"size"`
+	FetchedAt time.Time `json:"fetchedAt"`
+	LastUsed  time.Time `json:"lastUsed"`
+}
+
+// DefaultDir returns the cache directory, DBEERER_CACHE_DIR or
+// dbeerer/ under the user cache dir
+func DefaultDir() string {
+	if dir := os.Getenv("DBEERER_CACHE_DIR"); dir != "" {
+		return dir
+	}
+
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return filepath.Join(".cache", "dbeerer")
+	}
+
+	return filepath.Join(dir, "dbeerer")
+}
+
+// New returns the cache rooted at DefaultDir
+func New() *Cache {
+	return Open(DefaultDir())
+}
+
+// Open returns the cache rooted at dir, it is created on first write
+func Open(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// Dir returns the root directory of the cache
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// Lookup returns the entry of a repository and ref, or nil when it isn't
+// cached or its blob is gone
+func (c *Cache) Lookup(repo, ref string) (*Entry, error) {
+	entry, err := c.readEntry(c.entryPath(repo, ref))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := os.Stat(c.blobPath(entry.Digest)); err != nil {
+		return nil, nil
+	}
+
+	return entry, nil
+}
+
+// OpenBlob opens the blob of an entry and marks the entry as used
+func (c *Cache) OpenBlob(entry *Entry) (*os.File, error) {
+	file, err := os.Open(c.blobPath(entry.Digest))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cached tarball: %w", err)
+	}
+
+	entry.LastUsed = time.Now()
+	if err := c.writeEntry(entry); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// Store copies reader into a blob and records it for repo and ref
+func (c *Cache) Store(repo, ref, etag string, reader io.Reader) (*Entry, error) {
+	blobDir := filepath.Join(c.dir, "blobs", "sha256")
+	if err := os.MkdirAll(blobDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	temp, err := os.CreateTemp(blobDir, ".download-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cache file: %w", err)
+	}
+	defer os.Remove(temp.Name())
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(temp, hash), reader)
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write cache file: %w", err)
+	}
+
+	digest := hex.EncodeToString(hash.Sum(nil))
+	if err := os.Rename(temp.Name(), c.blobPath(digest)); err != nil {
+		return nil, fmt.Errorf("failed to store cache file: %w", err)
+	}
+
+	now := time.Now()
+	entry := &Entry{
+		Repo:      repo,
+		Ref:       ref,
+		ETag:      etag,
+		Digest:    digest,
+		Size:      size,
+		FetchedAt: now,
+		LastUsed:  now,
+	}
+	if err := c.writeEntry(entry); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// Touch records that an entry was revalidated with the server
+func (c *Cache) Touch(entry *Entry) error {
+	entry.FetchedAt = time.Now()
+	return c.writeEntry(entry)
+}
+
+// List returns all entries, sorted by repository and ref
+func (c *Cache) List() ([]Entry, error) {
+	paths, err := filepath.Glob(filepath.Join(c.dir, "entries", "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(paths))
+	for _, path := range paths {
+		entry, err := c.readEntry(path)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Repo != entries[j].Repo {
+			return entries[i].Repo < entries[j].Repo
+		}
+		return entries[i].Ref < entries[j].Ref
+	})
+
+	return entries, nil
+}
+
+// Prune removes entries unused for longer than maxAge, and blobs no entry
+// refers to anymore. It returns the number of bytes freed
+func (c *Cache) Prune(maxAge time.Duration) (int64, error) {
+	entries, err := c.List()
+	if err != nil {
+		return 0, err
+	}
+
+	cutoff := time.Now().Add(-maxAge)
+	used := map[string]bool{}
+	for i := range entries {
+		entry := &entries[i]
+		if entry.LastUsed.Before(cutoff) {
+			if err := os.Remove(c.entryPath(entry.Repo, entry.Ref)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return 0, fmt.Errorf("failed to remove cache entry: %w", err)
+			}
+			continue
+		}
+		used[entry.Digest] = true
+	}
+
+	blobs, err := filepath.Glob(filepath.Join(c.dir, "blobs", "sha256", "*"))
+	if err != nil {
+		return 0, err
+	}
+
+	var freed int64
+	for _, blob := range blobs {
+		// Dot files are downloads still in progress
+		if strings.HasPrefix(filepath.Base(blob), ".") {
+			continue
+		}
+		if used[strings.TrimSuffix(filepath.Base(blob), ".tar.gz")] {
+			continue
+		}
+		info, err := os.Stat(blob)
+		if err != nil {
+			continue
+		}
+		if err := os.Remove(blob); err != nil {
+			return freed, fmt.Errorf("failed to remove %s: %w", blob, err)
+		}
+		freed += info.Size()
+	}
+
+	return freed, nil
+}
+
+// Clear removes the whole cache
+func (c *Cache) Clear() error {
+	if err := os.RemoveAll(c.dir); err != nil {
+		return fmt.Errorf("failed to clear cache: %w", err)
+	}
+	return nil
+}
+
+// IsCommitSHA reports whether ref is a full commit SHA, whose content
+// never changes and needs no revalidation
+func IsCommitSHA(ref string) bool {
+	if len(ref) != 40 {
+		return false
+	}
+	_, err := hex.DecodeString(ref)
+	return err == nil
+}
+
+// entryPath returns the file holding the entry of a repository and ref
+func (c *Cache) entryPath(repo, ref string) string {
+	key := sha256.Sum256([]byte(repo + "@" + ref))
+	return filepath.Join(c.dir, "entries", hex.EncodeToString(key[:])+".json")
+}
+
+// blobPath returns the file holding the tarball with the given digest
+func (c *Cache) blobPath(digest string) string {
+	return filepath.Join(c.dir, "blobs", "sha256", digest+".tar.gz")
+}
+
+// readEntry reads the entry stored in path
+func (c *Cache) readEntry(path string) (*Entry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("failed to parse cache entry %s: %w", path, err)
+	}
+	return &entry, nil
+}
+
+// writeEntry stores an entry under its repository and ref
+func (c *Cache) writeEntry(entry *Entry) error {
+	path := c.entryPath(entry.Repo, entry.Ref)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write then rename so concurrent starts never read half an entry
+	temp, err := os.CreateTemp(filepath.Dir(path), ".entry-")
+	if err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	defer os.Remove(temp.Name())
+
+	_, err = temp.Write(data)
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(temp.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(temp.Name(), path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	return nil
+}
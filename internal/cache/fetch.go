@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/progress"
+)
+
+// ErrNotFound is returned by Fetch when the server answers 404
+var ErrNotFound = errors.New("not found")
+
+// Doer sends HTTP requests, like http.Client.Do
+type Doer func(*http.Request) (*http.Response, error)
+
+// Fetch returns the tarball at url for repo and ref, downloading it only
+// when the server has a newer one than the cached copy
+// Immutable refs, like commit SHAs, are served from the cache without
+// asking. When the server can't be reached a cached tarball is used as is
+// Downloads are reported to report
+func (c *Cache) Fetch(repo, ref, url string, immutable bool, do Doer, report progress.Reporter) (*os.File, error) {
+	entry, err := c.Lookup(repo, ref)
+	if err != nil {
+		return nil, err
+	}
+	if entry != nil && immutable {
+		fmt.Printf("📦 Using cached %s@%s\n", repo, ref)
+		return c.OpenBlob(entry)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if entry != nil && entry.ETag != "" {
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+
+	resp, err := do(req)
+	if err != nil {
+		if entry != nil {
+			fmt.Printf("⚠️  Download failed, using cached %s@%s from %s: %v\n", repo, ref, entry.FetchedAt.Format(time.RFC3339), err)
+			return c.OpenBlob(entry)
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && entry != nil:
+		fmt.Printf("📦 Cached %s@%s is up to date\n", repo, ref)
+		if err := c.Touch(entry); err != nil {
+			return nil, err
+		}
+		return c.OpenBlob(entry)
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%s: %w", url, ErrNotFound)
+	case resp.StatusCode != http.StatusOK:
+		if entry != nil && resp.StatusCode >= http.StatusInternalServerError {
+			fmt.Printf("⚠️  Download failed with HTTP %d, using cached %s@%s\n", resp.StatusCode, repo, ref)
+			return c.OpenBlob(entry)
+		}
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	report.Start(repo+"@"+ref, resp.ContentLength)
+	entry, err = c.Store(repo, ref, resp.Header.Get("ETag"), progress.Reader(resp.Body, report))
+	report.Finish(err)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.OpenBlob(entry)
+}
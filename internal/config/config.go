@@ -0,0 +1,577 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Default values used when nothing else sets them
+const (
+	DefaultDomain            = "devopsbeerer.local"
+	DefaultKubeconfig        = "/etc/rancher/k3s/k3s.yaml"
+	DefaultNamespacePrefix   = "devopsbeerer-"
+	DefaultPlaygroundRepoURL = "https://github.com/DevOpsBeerer/playground.git"
+	DefaultChartSource       = "https://github.com/DevOpsBeerer/playground-scenarios-charts"
+	DefaultChartRef          = "main"
+
+	DefaultRequestTimeout   = 10 * time.Second
+	DefaultDownloadTimeout  = 30 * time.Second
+	DefaultInstallTimeout   = 10 * time.Minute
+	DefaultUninstallTimeout = 5 * time.Minute
+	DefaultWaitTimeout      = 2 * time.Minute
+)
+
+// EnvPrefix prefixes the environment variable of every config key
+const EnvPrefix = "DBEERER_"
+
+// Sources a resolved value can come from, in order of precedence
+const (
+	SourceFlag    = "flag"
+	SourceEnv     = "env"
+	SourceProfile = "profile"
+	SourceFile    = "file"
+	SourceDefault = "default"
+)
+
+// Config is the effective configuration used by the CLI
+type Config struct {
+	// Profile is the name of the profile the values were resolved from
+	Profile           string
+	Domain            string
+	Kubeconfig        string
+	NamespacePrefix   string
+	PlaygroundRepoURL string
+	ChartSource       string
+	// ChartRef is the branch, tag or commit charts are fetched from when a
+	// scenario doesn't pin one
+	ChartRef string
+	// GitHubToken authenticates GitHub requests, raising the API rate limit
+	GitHubToken string
+	// GitLabToken authenticates GitLab requests, for private projects
+	GitLabToken string
+	// MultiScenario allows running several scenarios side by side
+	MultiScenario bool
+	// Quiet suppresses per-file output such as extracted chart files
+	Quiet    bool
+	Timeouts Timeouts
+	Registry Registry
+	HTTP     HTTP
+
+	values map[string]Value
+}
+
+// Timeouts holds the per-operation timeouts
+type Timeouts struct {
+	// Request bounds a single Kubernetes or HTTP API request
+	Request time.Duration
+	// Download bounds a chart or container image download
+	Download time.Duration
+	// Install bounds a Helm install including its wait
+	Install time.Duration
+	// Uninstall bounds a Helm uninstall including its wait
+	Uninstall time.Duration
+	// Wait bounds polling for cluster resources to reach a state
+	Wait time.Duration
+}
+
+// Registry holds the settings used to pull charts from OCI registries
+type Registry struct {
+	// Username and Password log in to the registry of every oci:// chart,
+	// Helm and Docker credentials are used when unset
+	Username string
+	Password string
+	// PlainHTTP talks to registries over HTTP instead of HTTPS
+	PlainHTTP bool
+}
+
+// HTTP holds the settings of outbound HTTP requests and git
+type HTTP struct {
+	// CABundle is a PEM file of certificates trusted on top of the system
+	// ones, e.g. the CA of a TLS-intercepting proxy
+	CABundle string
+	// InsecureSkipVerify disables TLS certificate verification
+	InsecureSkipVerify bool
+	// UserAgent replaces the default dbeerer/<version> user agent
+	UserAgent string
+}
+
+// Value is a resolved config value together with where it came from
+type Value struct {
+	Key    string
+	Value  string
+	Source string
+	// Secret values are masked when listed
+	Secret bool
+}
+
+// File represents the on-disk configuration file
+// Values at the top level apply to every profile; a named profile
+// overrides them
+type File struct {
+	Profile
+	CurrentProfile string              `json:"currentProfile,omitempty"`
+	Profiles       map[string]*Profile `json:"profiles,omitempty"`
+}
+
+// Profile is a named set of configuration values
+type Profile struct {
+	Domain            string            `json:"domain,omitempty"`
+	Kubeconfig        string            `json:"kubeconfig,omitempty"`
+	NamespacePrefix   string            `json:"namespacePrefix,omitempty"`
+	PlaygroundRepoURL string            `json:"playgroundRepoURL,omitempty"`
+	ChartSource       string            `json:"chartSource,omitempty"`
+	ChartRef          string            `json:"chartRef,omitempty"`
+	GitHubToken       string            `json:"githubToken,omitempty"`
+	GitLabToken       string            `json:"gitlabToken,omitempty"`
+	MultiScenario     string            `json:"multiScenario,omitempty"`
+	Quiet             string            `json:"quiet,omitempty"`
+	Timeout           string            `json:"timeout,omitempty"`
+	Timeouts          *TimeoutSettings  `json:"timeouts,omitempty"`
+	Registry          *RegistrySettings `json:"registry,omitempty"`
+	HTTP              *HTTPSettings     `json:"http,omitempty"`
+}
+
+// timeouts returns the profile's timeout settings, allocating them on first use
+func (p *Profile) timeouts() *TimeoutSettings {
+	if p.Timeouts == nil {
+		p.Timeouts = &TimeoutSettings{}
+	}
+	return p.Timeouts
+}
+
+// registry returns the profile's registry settings, allocating them on first use
+func (p *Profile) registry() *RegistrySettings {
+	if p.Registry == nil {
+		p.Registry = &RegistrySettings{}
+	}
+	return p.Registry
+}
+
+// http returns the profile's HTTP settings, allocating them on first use
+func (p *Profile) http() *HTTPSettings {
+	if p.HTTP == nil {
+		p.HTTP = &HTTPSettings{}
+	}
+	return p.HTTP
+}
+
+// compact drops empty nested settings so they aren't written out
+func (p *Profile) compact() {
+	if p.Timeouts != nil && *p.Timeouts == (TimeoutSettings{}) {
+		p.Timeouts = nil
+	}
+	if p.Registry != nil && *p.Registry == (RegistrySettings{}) {
+		p.Registry = nil
+	}
+	if p.HTTP != nil && *p.HTTP == (HTTPSettings{}) {
+		p.HTTP = nil
+	}
+}
+
+// TimeoutSettings holds per-operation timeouts as written in the config file
+type TimeoutSettings struct {
+	Request   string `json:"request,omitempty"`
+	Download  string `json:"download,omitempty"`
+	Install   string `json:"install,omitempty"`
+	Uninstall string `json:"uninstall,omitempty"`
+	Wait      string `json:"wait,omitempty"`
+}
+
+// RegistrySettings holds the OCI registry settings as written in the config file
+type RegistrySettings struct {
+	Username  string `json:"username,omitempty"`
+	Password  string `json:"password,omitempty"`
+	PlainHTTP string `json:"plainHTTP,omitempty"`
+}
+
+// HTTPSettings holds the HTTP settings as written in the config file
+type HTTPSettings struct {
+	CABundle           string `json:"caBundle,omitempty"`
+	InsecureSkipVerify string `json:"insecureSkipVerify,omitempty"`
+	UserAgent          string `json:"userAgent,omitempty"`
+}
+
+// key describes a single configuration value
+type key struct {
+	name string
+	// env lists extra environment variables checked after the DBEERER_ one
+	env []string
+	// fallback is checked at the same precedence level when the key itself is unset
+	fallback string
+	def      string
+	// secret values are masked when listed
+	secret   bool
+	validate func(string) error
+	field    func(*Profile) *string
+}
+
+var keys = []key{
+	{name: "domain", def: DefaultDomain, field: func(p *Profile) *string { return &p.Domain }},
+	{name: "kubeconfig", env: []string{"KUBECONFIG"}, def: DefaultKubeconfig, field: func(p *Profile) *string { return &p.Kubeconfig }},
+	{name: "namespacePrefix", def: DefaultNamespacePrefix, field: func(p *Profile) *string { return &p.NamespacePrefix }},
+	{name: "playgroundRepoURL", def: DefaultPlaygroundRepoURL, field: func(p *Profile) *string { return &p.PlaygroundRepoURL }},
+	{name: "chartSource", def: DefaultChartSource, field: func(p *Profile) *string { return &p.ChartSource }},
+	{name: "chartRef", def: DefaultChartRef, field: func(p *Profile) *string { return &p.ChartRef }},
+	{name: "githubToken", env: []string{"GITHUB_TOKEN", "GH_TOKEN"}, secret: true, field: func(p *Profile) *string { return &p.GitHubToken }},
+	{name: "gitlabToken", env: []string{"GITLAB_TOKEN"}, secret: true, field: func(p *Profile) *string { return &p.GitLabToken }},
+	{name: "multiScenario", def: "false", validate: validateBool, field: func(p *Profile) *string { return &p.MultiScenario }},
+	{name: "quiet", def: "false", validate: validateBool, field: func(p *Profile) *string { return &p.Quiet }},
+	{name: "timeout", validate: validateDuration, field: func(p *Profile) *string { return &p.Timeout }},
+	{name: "timeouts.request", fallback: "timeout", def: DefaultRequestTimeout.String(), validate: validateDuration, field: func(p *Profile) *string { return &p.timeouts().Request }},
+	{name: "timeouts.download", fallback: "timeout", def: DefaultDownloadTimeout.String(), validate: validateDuration, field: func(p *Profile) *string { return &p.timeouts().Download }},
+	{name: "timeouts.install", fallback: "timeout", def: DefaultInstallTimeout.String(), validate: validateDuration, field: func(p *Profile) *string { return &p.timeouts().Install }},
+	{name: "timeouts.uninstall", fallback: "timeout", def: DefaultUninstallTimeout.String(), validate: validateDuration, field: func(p *Profile) *string { return &p.timeouts().Uninstall }},
+	{name: "timeouts.wait", fallback: "timeout", def: DefaultWaitTimeout.String(), validate: validateDuration, field: func(p *Profile) *string { return &p.timeouts().Wait }},
+	{name: "registry.username", field: func(p *Profile) *string { return &p.registry().Username }},
+	{name: "registry.password", secret: true, field: func(p *Profile) *string { return &p.registry().Password }},
+	{name: "registry.plainHTTP", def: "false", validate: validateBool, field: func(p *Profile) *string { return &p.registry().PlainHTTP }},
+	{name: "http.caBundle", field: func(p *Profile) *string { return &p.http().CABundle }},
+	{name: "http.insecureSkipVerify", def: "false", validate: validateBool, field: func(p *Profile) *string { return &p.http().InsecureSkipVerify }},
+	{name: "http.userAgent", field: func(p *Profile) *string { return &p.http().UserAgent }},
+}
+
+// Keys returns the names of all configuration keys
+func Keys() []string {
+	names := make([]string, 0, len(keys))
+	for _, k := range keys {
+		names = append(names, k.name)
+	}
+	return names
+}
+
+// EnvName returns the environment variable that sets a config key
+func EnvName(name string) string {
+	return EnvPrefix + strings.ToUpper(strings.NewReplacer(".", "_").Replace(camelToSnake(name)))
+}
+
+// camelToSnake converts playgroundRepoURL into playground_Repo_URL
+func camelToSnake(name string) string {
+	var b strings.Builder
+	var prev rune
+	for _, r := range name {
+		if unicode.IsUpper(r) && unicode.IsLower(prev) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(r)
+		prev = r
+	}
+	return b.String()
+}
+
+// DefaultPath returns the location of the user configuration file
+func DefaultPath() string {
+	if path := os.Getenv(EnvPrefix + "CONFIG"); path != "" {
+		return path
+	}
+
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "dbeerer", "config.yaml")
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".config", "dbeerer", "config.yaml")
+	}
+
+	return filepath.Join(home, ".config", "dbeerer", "config.yaml")
+}
+
+// Load reads the configuration file at path
+// A missing file is not an error and yields an empty configuration
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &File{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	file := &File{}
+	if err := yaml.UnmarshalStrict(data, file); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return file, nil
+}
+
+// Save writes the configuration file to path
+func (f *File) Save(path string) error {
+	f.Profile.compact()
+	for _, profile := range f.Profiles {
+		profile.compact()
+	}
+
+	data, err := yaml.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	return nil
+}
+
+// ProfileNames returns the sorted names of the profiles in the file
+func (f *File) ProfileNames() []string {
+	names := make([]string, 0, len(f.Profiles))
+	for name := range f.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// UseProfile makes name the current profile
+func (f *File) UseProfile(name string) error {
+	if _, ok := f.Profiles[name]; !ok {
+		return fmt.Errorf("profile '%s' not found", name)
+	}
+	f.CurrentProfile = name
+	return nil
+}
+
+// Set stores value for key in the named profile, or at the top level when
+// profile is empty. The profile is created if it doesn't exist yet
+func (f *File) Set(profile, name, value string) error {
+	k, err := lookupKey(name)
+	if err != nil {
+		return err
+	}
+
+	if k.validate != nil && value != "" {
+		if err := k.validate(value); err != nil {
+			return fmt.Errorf("invalid value for %s: %w", name, err)
+		}
+	}
+
+	target := &f.Profile
+	if profile != "" {
+		if f.Profiles == nil {
+			f.Profiles = map[string]*Profile{}
+		}
+		if f.Profiles[profile] == nil {
+			f.Profiles[profile] = &Profile{}
+		}
+		target = f.Profiles[profile]
+	}
+
+	*k.field(target) = value
+	return nil
+}
+
+// SecretMask replaces the values of secret keys in output
+const SecretMask = "********"
+
+// MaskSecrets replaces the secret values of every profile with SecretMask,
+// for printing the file
+func (f *File) MaskSecrets() {
+	profiles := []*Profile{&f.Profile}
+	for _, profile := range f.Profiles {
+		profiles = append(profiles, profile)
+	}
+
+	for _, profile := range profiles {
+		for _, k := range keys {
+			if value := k.field(profile); k.secret && *value != "" {
+				*value = SecretMask
+			}
+		}
+		profile.compact()
+	}
+}
+
+// Resolve computes the effective configuration for a profile
+// Each value resolves from overrides (command-line flags), then the
+// environment, then the profile, then the top level of the file, and
+// finally the built-in default. An empty profile selects the file's
+// current profile
+func Resolve(file *File, profile string, overrides map[string]string) (*Config, error) {
+	if profile == "" {
+		profile = file.CurrentProfile
+	}
+
+	var named *Profile
+	if profile != "" {
+		named = file.Profiles[profile]
+		if named == nil {
+			return nil, fmt.Errorf("profile '%s' not found", profile)
+		}
+	}
+
+	cfg := &Config{
+		Profile: profile,
+		values:  make(map[string]Value, len(keys)),
+	}
+
+	for _, k := range keys {
+		value, source := resolveKey(k, file, named, overrides)
+		cfg.values[k.name] = Value{Key: k.name, Value: value, Source: source, Secret: k.secret}
+	}
+
+	cfg.Domain = cfg.values["domain"].Value
+	cfg.Kubeconfig = cfg.values["kubeconfig"].Value
+	cfg.NamespacePrefix = cfg.values["namespacePrefix"].Value
+	cfg.PlaygroundRepoURL = cfg.values["playgroundRepoURL"].Value
+	cfg.ChartSource = cfg.values["chartSource"].Value
+	cfg.ChartRef = cfg.values["chartRef"].Value
+	cfg.GitHubToken = cfg.values["githubToken"].Value
+	cfg.GitLabToken = cfg.values["gitlabToken"].Value
+
+	multiScenario := cfg.values["multiScenario"]
+	enabled, err := strconv.ParseBool(multiScenario.Value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for multiScenario (from %s): %w", multiScenario.Source, err)
+	}
+	cfg.MultiScenario = enabled
+
+	quiet := cfg.values["quiet"]
+	cfg.Quiet, err = strconv.ParseBool(quiet.Value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for quiet (from %s): %w", quiet.Source, err)
+	}
+
+	cfg.Registry.Username = cfg.values["registry.username"].Value
+	cfg.Registry.Password = cfg.values["registry.password"].Value
+	plainHTTP := cfg.values["registry.plainHTTP"]
+	cfg.Registry.PlainHTTP, err = strconv.ParseBool(plainHTTP.Value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for registry.plainHTTP (from %s): %w", plainHTTP.Source, err)
+	}
+
+	cfg.HTTP.CABundle = cfg.values["http.caBundle"].Value
+	cfg.HTTP.UserAgent = cfg.values["http.userAgent"].Value
+	insecure := cfg.values["http.insecureSkipVerify"]
+	cfg.HTTP.InsecureSkipVerify, err = strconv.ParseBool(insecure.Value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for http.insecureSkipVerify (from %s): %w", insecure.Source, err)
+	}
+
+	durations := []struct {
+		key    string
+		target *time.Duration
+	}{
+		{"timeouts.request", &cfg.Timeouts.Request},
+		{"timeouts.download", &cfg.Timeouts.Download},
+		{"timeouts.install", &cfg.Timeouts.Install},
+		{"timeouts.uninstall", &cfg.Timeouts.Uninstall},
+		{"timeouts.wait", &cfg.Timeouts.Wait},
+	}
+
+	for _, d := range durations {
+		value := cfg.values[d.key]
+		parsed, err := parseDuration(value.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s (from %s): %w", d.key, value.Source, err)
+		}
+		*d.target = parsed
+	}
+
+	return cfg, nil
+}
+
+// Get returns the resolved value of a config key
+func (c *Config) Get(name string) (Value, error) {
+	value, ok := c.values[name]
+	if !ok {
+		return Value{}, fmt.Errorf("unknown config key '%s'", name)
+	}
+	return value, nil
+}
+
+// Values returns every resolved value in key order
+func (c *Config) Values() []Value {
+	values := make([]Value, 0, len(keys))
+	for _, k := range keys {
+		values = append(values, c.values[k.name])
+	}
+	return values
+}
+
+// resolveKey walks the precedence levels and returns the first value set
+func resolveKey(k key, file *File, named *Profile, overrides map[string]string) (string, string) {
+	levels := []struct {
+		source string
+		lookup func(k key) string
+	}{
+		{SourceFlag, func(k key) string { return overrides[k.name] }},
+		{SourceEnv, func(k key) string {
+			for _, env := range append([]string{EnvName(k.name)}, k.env...) {
+				if value := os.Getenv(env); value != "" {
+					return value
+				}
+			}
+			return ""
+		}},
+		{SourceProfile, func(k key) string {
+			if named == nil {
+				return ""
+			}
+			return *k.field(named)
+		}},
+		{SourceFile, func(k key) string { return *k.field(&file.Profile) }},
+	}
+
+	for _, level := range levels {
+		if value := level.lookup(k); value != "" {
+			return value, level.source
+		}
+		if k.fallback != "" {
+			fallback, _ := lookupKey(k.fallback)
+			if value := level.lookup(fallback); value != "" {
+				return value, level.source
+			}
+		}
+	}
+
+	return k.def, SourceDefault
+}
+
+// lookupKey returns the key definition for name
+func lookupKey(name string) (key, error) {
+	for _, k := range keys {
+		if k.name == name {
+			return k, nil
+		}
+	}
+	return key{}, fmt.Errorf("unknown config key '%s' (known keys: %s)", name, strings.Join(Keys(), ", "))
+}
+
+// validateBool checks that value is a boolean
+func validateBool(value string) error {
+	_, err := strconv.ParseBool(value)
+	return err
+}
+
+// validateDuration checks that value is a positive duration
+func validateDuration(value string) error {
+	_, err := parseDuration(value)
+	return err
+}
+
+// parseDuration parses a positive duration
+func parseDuration(value string) (time.Duration, error) {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("duration must be positive, got %s", value)
+	}
+	return d, nil
+}
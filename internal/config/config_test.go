@@ -0,0 +1,210 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+// clearEnv unsets every environment variable a key reads, so the
+// developer's environment doesn't leak into the tests
+func clearEnv(t *testing.T) {
+	t.Helper()
+
+	for _, k := range keys {
+		for _, env := range append([]string{EnvName(k.name)}, k.env...) {
+			t.Setenv(env, "")
+		}
+	}
+}
+
+// fileWith returns a config file with the given top-level and profile
+// values, the profile is current when it has values
+func fileWith(t *testing.T, top, profile map[string]string) *File {
+	t.Helper()
+
+	file := &File{}
+	for name, value := range top {
+		if err := file.Set("", name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, value := range profile {
+		if err := file.Set("class", name, value); err != nil {
+			t.Fatal(err)
+		}
+		file.CurrentProfile = "class"
+	}
+	return file
+}
+
+func TestResolvePrecedence(t *testing.T) {
+	tests := []struct {
+		name       string
+		key        string
+		top        map[string]string
+		profile    map[string]string
+		env        map[string]string
+		flags      map[string]string
+		wantValue  string
+		wantSource string
+	}{
+		{
+			name:       "default",
+			key:        "domain",
+			wantValue:  DefaultDomain,
+			wantSource: SourceDefault,
+		},
+		{
+			name:       "file over default",
+			key:        "domain",
+			top:        map[string]string{"domain": "file.local"},
+			wantValue:  "file.local",
+			wantSource: SourceFile,
+		},
+		{
+			name:       "profile over file",
+			key:        "domain",
+			top:        map[string]string{"domain": "file.local"},
+			profile:    map[string]string{"domain": "profile.local"},
+			wantValue:  "profile.local",
+			wantSource: SourceProfile,
+		},
+		{
+			name:       "env over profile",
+			key:        "domain",
+			top:        map[string]string{"domain": "file.local"},
+			profile:    map[string]string{"domain": "profile.local"},
+			env:        map[string]string{"DBEERER_DOMAIN": "env.local"},
+			wantValue:  "env.local",
+			wantSource: SourceEnv,
+		},
+		{
+			name:       "flag over env",
+			key:        "domain",
+			top:        map[string]string{"domain": "file.local"},
+			profile:    map[string]string{"domain": "profile.local"},
+			env:        map[string]string{"DBEERER_DOMAIN": "env.local"},
+			flags:      map[string]string{"domain": "flag.local"},
+			wantValue:  "flag.local",
+			wantSource: SourceFlag,
+		},
+		{
+			name:       "extra env variable",
+			key:        "kubeconfig",
+			top:        map[string]string{"kubeconfig": "/file/config"},
+			env:        map[string]string{"KUBECONFIG": "/env/config"},
+			wantValue:  "/env/config",
+			wantSource: SourceEnv,
+		},
+		{
+			name:       "prefixed env over extra env variable",
+			key:        "githubToken",
+			env:        map[string]string{"DBEERER_GITHUB_TOKEN": "dbeerer", "GITHUB_TOKEN": "github"},
+			wantValue:  "dbeerer",
+			wantSource: SourceEnv,
+		},
+		{
+			name:       "fallback at a higher level wins",
+			key:        "timeouts.request",
+			top:        map[string]string{"timeouts.request": "5s"},
+			profile:    map[string]string{"timeout": "1m"},
+			wantValue:  "1m",
+			wantSource: SourceProfile,
+		},
+		{
+			name:       "key over fallback at the same level",
+			key:        "timeouts.request",
+			flags:      map[string]string{"timeout": "1m", "timeouts.request": "5s"},
+			wantValue:  "5s",
+			wantSource: SourceFlag,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			cfg, err := Resolve(fileWith(t, tt.top, tt.profile), "", tt.flags)
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+
+			got, err := cfg.Get(tt.key)
+			if err != nil {
+				t.Fatalf("Get(%s) error = %v", tt.key, err)
+			}
+			if got.Value != tt.wantValue || got.Source != tt.wantSource {
+				t.Errorf("%s = %s (from %s), want %s (from %s)", tt.key, got.Value, got.Source, tt.wantValue, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestResolveUnknownProfile(t *testing.T) {
+	clearEnv(t)
+
+	tests := []struct {
+		name    string
+		file    *File
+		profile string
+	}{
+		{name: "requested profile", file: fileWith(t, nil, map[string]string{"domain": "class.local"}), profile: "missing"},
+		{name: "current profile", file: &File{CurrentProfile: "missing"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Resolve(tt.file, tt.profile, nil)
+			if err == nil || !strings.Contains(err.Error(), "profile 'missing' not found") {
+				t.Fatalf("Resolve() error = %v, want profile not found", err)
+			}
+		})
+	}
+}
+
+func TestMaskSecrets(t *testing.T) {
+	file := fileWith(t,
+		map[string]string{"domain": "file.local", "githubToken": "ghp_top"},
+		map[string]string{"gitlabToken": "glpat_class", "registry.username": "beerer", "registry.password": "cheers"},
+	)
+	if err := file.Set("empty", "domain", "empty.local"); err != nil {
+		t.Fatal(err)
+	}
+
+	file.MaskSecrets()
+
+	tests := []struct {
+		profile *Profile
+		key     string
+		want    string
+	}{
+		{profile: &file.Profile, key: "githubToken", want: SecretMask},
+		{profile: &file.Profile, key: "domain", want: "file.local"},
+		{profile: file.Profiles["class"], key: "gitlabToken", want: SecretMask},
+		{profile: file.Profiles["class"], key: "registry.password", want: SecretMask},
+		{profile: file.Profiles["class"], key: "registry.username", want: "beerer"},
+		{profile: file.Profiles["empty"], key: "githubToken", want: ""},
+		{profile: file.Profiles["empty"], key: "registry.password", want: ""},
+	}
+
+	for _, tt := range tests {
+		k, err := lookupKey(tt.key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := *k.field(tt.profile); got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+
+	// Reading the keys above allocates nested settings, MaskSecrets itself
+	// must not leave empty ones behind
+	masked := fileWith(t, nil, map[string]string{"domain": "class.local"})
+	masked.MaskSecrets()
+	if profile := masked.Profiles["class"]; profile.Registry != nil || profile.Timeouts != nil || profile.HTTP != nil {
+		t.Errorf("MaskSecrets() left empty nested settings: %+v", profile)
+	}
+}
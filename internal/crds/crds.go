@@ -0,0 +1,275 @@
+package crds
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/config"
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/kube"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// VersionAnnotation records the revision of a DevOpsBeerer CRD manifest
+	VersionAnnotation = "devopsbeerer.ch/crd-version"
+	// FieldManager owns the fields the CLI applies
+	FieldManager = "dbeerer"
+	// pollInterval is how often the CRD conditions are checked while waiting
+	pollInterval = time.Second
+)
+
+//go:embed manifests/*.yaml
+var manifests embed.FS
+
+var crdGVR = schema.GroupVersionResource{
+	Group:    "apiextensions.k8s.io",
+	Version:  "v1",
+	Resource: "customresourcedefinitions",
+}
+
+// Manager installs and inspects the DevOpsBeerer CRDs
+type Manager struct {
+	dynamicClient dynamic.Interface
+	timeouts      config.Timeouts
+}
+
+// Status describes a CRD in the cluster compared with the embedded manifest
+type Status struct {
+	Name            string
+	Installed       bool
+	Established     bool
+	ClusterVersion  string
+	ExpectedVersion string
+}
+
+// Outdated reports whether the cluster runs an older CRD than the CLI expects
+// A CRD without a version annotation predates versioning and counts as outdated
+func (s Status) Outdated() bool {
+	if !s.Installed {
+		return false
+	}
+	return parseVersion(s.ClusterVersion) < parseVersion(s.ExpectedVersion)
+}
+
+// Newer reports whether the cluster runs a newer CRD than the CLI knows,
+// which applying the embedded manifest would downgrade
+func (s Status) Newer() bool {
+	if !s.Installed {
+		return false
+	}
+	return parseVersion(s.ClusterVersion) > parseVersion(s.ExpectedVersion)
+}
+
+// NewManager creates a new CRD manager
+func NewManager(cfg *config.Config) (*Manager, error) {
+	restConfig, err := kube.RESTConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
+	return &Manager{
+		dynamicClient: dynamicClient,
+		timeouts:      cfg.Timeouts,
+	}, nil
+}
+
+// Manifests returns the CRD manifests embedded in the binary
+func Manifests() ([]*unstructured.Unstructured, error) {
+	entries, err := manifests.ReadDir("manifests")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedded CRDs: %w", err)
+	}
+
+	crds := make([]*unstructured.Unstructured, 0, len(entries))
+	for _, entry := range entries {
+		data, err := manifests.ReadFile(path.Join("manifests", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read embedded CRD %s: %w", entry.Name(), err)
+		}
+
+		crd := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal(data, &crd.Object); err != nil {
+			return nil, fmt.Errorf("failed to parse embedded CRD %s: %w", entry.Name(), err)
+		}
+		crds = append(crds, crd)
+	}
+
+	return crds, nil
+}
+
+// Status compares every embedded CRD with the one in the cluster
+func (m *Manager) Status() ([]Status, error) {
+	crds, err := Manifests()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(crds))
+	for _, crd := range crds {
+		status := Status{
+			Name:            crd.GetName(),
+			ExpectedVersion: crd.GetAnnotations()[VersionAnnotation],
+		}
+
+		current, err := m.dynamicClient.Resource(crdGVR).Get(context.TODO(), crd.GetName(), metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			statuses = append(statuses, status)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get CRD %s: %w", crd.GetName(), err)
+		}
+
+		status.Installed = true
+		status.Established = isEstablished(current)
+		status.ClusterVersion = current.GetAnnotations()[VersionAnnotation]
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// Install applies the CRDs missing from the cluster and leaves existing ones untouched
+func (m *Manager) Install() error {
+	return m.apply(false, false)
+}
+
+// Upgrade applies every CRD, taking over fields owned by other managers
+// CRDs newer than the embedded ones are only downgraded with force
+func (m *Manager) Upgrade(force bool) error {
+	return m.apply(true, force)
+}
+
+// apply server-side applies the embedded CRDs and waits for them to be established
+func (m *Manager) apply(upgrade, force bool) error {
+	crds, err := Manifests()
+	if err != nil {
+		return err
+	}
+
+	statuses, err := m.Status()
+	if err != nil {
+		return err
+	}
+
+	// Refuse before applying anything, so CRDs aren't left half downgraded
+	if upgrade && !force {
+		for _, status := range statuses {
+			if status.Newer() {
+				return fmt.Errorf("%s in the cluster is version %s, newer than version %s of this CLI; upgrade dbeerer, or pass --force to downgrade it",
+					status.Name, status.ClusterVersion, status.ExpectedVersion)
+			}
+		}
+	}
+
+	for i, crd := range crds {
+		status := statuses[i]
+
+		if status.Installed && !upgrade {
+			fmt.Printf("ℹ️  %s already installed (version %s)\n", status.Name, versionOrUnknown(status.ClusterVersion))
+			if status.Outdated() {
+				fmt.Printf("⚠️  %s is older than version %s expected by this CLI, run: dbeerer crds upgrade\n", status.Name, status.ExpectedVersion)
+			}
+			continue
+		}
+
+		if status.Newer() {
+			fmt.Printf("⚠️  Downgrading %s from version %s\n", status.Name, status.ClusterVersion)
+		}
+		fmt.Printf("📝 Applying %s (version %s)...\n", status.Name, status.ExpectedVersion)
+
+		_, err := m.dynamicClient.Resource(crdGVR).Apply(context.TODO(), crd.GetName(), crd, metav1.ApplyOptions{
+			FieldManager: FieldManager,
+			Force:        true,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to apply CRD %s: %w", crd.GetName(), err)
+		}
+
+		if err := m.waitEstablished(crd.GetName()); err != nil {
+			return err
+		}
+
+		fmt.Printf("✅ %s established\n", crd.GetName())
+	}
+
+	return nil
+}
+
+// waitEstablished polls until the CRD reports the Established condition
+// A CRD that isn't visible yet is waited for, other errors end the wait
+func (m *Manager) waitEstablished(name string) error {
+	deadline := time.Now().Add(m.timeouts.Wait)
+
+	var lastErr error
+	for {
+		crd, err := m.dynamicClient.Resource(crdGVR).Get(context.TODO(), name, metav1.GetOptions{})
+		switch {
+		case err == nil && isEstablished(crd):
+			return nil
+		case err == nil:
+			lastErr = nil
+		case apierrors.IsNotFound(err):
+			lastErr = err
+		default:
+			return fmt.Errorf("failed to get CRD %s: %w", name, err)
+		}
+
+		if time.Now().After(deadline) {
+			if lastErr != nil {
+				return fmt.Errorf("timed out after %s waiting for CRD %s to be established: %w", m.timeouts.Wait, name, lastErr)
+			}
+			return fmt.Errorf("timed out after %s waiting for CRD %s to be established", m.timeouts.Wait, name)
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// isEstablished reports whether a CRD has the Established=True condition
+func isEstablished(crd *unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(crd.Object, "status", "conditions")
+
+	for _, item := range conditions {
+		condition, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if condition["type"] == "Established" && condition["status"] == "True" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseVersion turns a version annotation into a comparable number
+// Missing or malformed versions count as zero
+func parseVersion(version string) int {
+	n, err := strconv.Atoi(version)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// versionOrUnknown returns version, or "unknown" when the annotation is missing
+func versionOrUnknown(version string) string {
+	if version == "" {
+		return "unknown"
+	}
+	return version
+}
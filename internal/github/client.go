@@ -0,0 +1,136 @@
+package github
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// maxAttempts bounds how often a request is sent before giving up
+	maxAttempts = 4
+	// initialBackoff is the first retry delay, doubled on every retry
+	initialBackoff = time.Second
+	// maxBackoff caps a single retry delay, including Retry-After
+	maxBackoff = time.Minute
+)
+
+// RateLimitError is returned when GitHub's primary rate limit is exhausted
+type RateLimitError struct {
+	Limit int
+	Reset time.Time
+	// Authenticated tells whether the request carried a token
+	Authenticated bool
+}
+
+func (e *RateLimitError) Error() string {
+	message := fmt.Sprintf("GitHub rate limit of %d requests per hour exceeded, it resets at %s (in %s)",
+		e.Limit, e.Reset.Local().Format("15:04:05"), time.Until(e.Reset).Round(time.Second))
+	if !e.Authenticated {
+		message += "; set GITHUB_TOKEN or 'dbeerer config set githubToken <token>' to raise the limit"
+	}
+	return message
+}
+
+// do sends a request to GitHub with the configured token, retrying with
+// exponential backoff on server errors and secondary rate limits
+// The request must not have a body
+func (d *Downloader) do(req *http.Request) (*http.Response, error) {
+	if d.token != "" {
+		req.Header.Set("Authorization", "Bearer "+d.token)
+	}
+
+	backoff := initialBackoff
+	for attempt := 1; ; attempt++ {
+		resp, err := d.httpClient.Do(req.Clone(req.Context()))
+		if err != nil {
+			return nil, err
+		}
+
+		if rateLimited := rateLimitError(resp, d.token != ""); rateLimited != nil {
+			resp.Body.Close()
+			return nil, rateLimited
+		}
+
+		retry, delay := shouldRetry(resp)
+		if !retry || attempt == maxAttempts {
+			return resp, nil
+		}
+		resp.Body.Close()
+
+		if delay == 0 {
+			delay = backoff
+			backoff *= 2
+		}
+		if delay > maxBackoff {
+			delay = maxBackoff
+		}
+
+		fmt.Printf("⏳ GitHub returned HTTP %d, retrying in %s (attempt %d/%d)...\n", resp.StatusCode, delay, attempt+1, maxAttempts)
+		time.Sleep(delay)
+	}
+}
+
+// rateLimitError returns an error when the response reports an exhausted
+// primary rate limit, nil otherwise
+func rateLimitError(resp *http.Response, authenticated bool) *RateLimitError {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return nil
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		return nil
+	}
+
+	limit, _ := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+	reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return nil
+	}
+
+	return &RateLimitError{
+		Limit:         limit,
+		Reset:         time.Unix(reset, 0),
+		Authenticated: authenticated,
+	}
+}
+
+// shouldRetry reports whether a response is worth retrying, and the delay
+// GitHub asked for, if any
+func shouldRetry(resp *http.Response) (bool, time.Duration) {
+	switch {
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return true, retryAfter(resp)
+	case resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests:
+		// Secondary rate limits come with Retry-After, or only say so in the body
+		if delay := retryAfter(resp); delay > 0 {
+			return true, delay
+		}
+		return isSecondaryRateLimit(resp), 0
+	default:
+		return false, 0
+	}
+}
+
+// retryAfter parses the Retry-After header given in seconds
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// isSecondaryRateLimit checks the error message GitHub sends when a
+// secondary rate limit was hit, leaving the body readable
+func isSecondaryRateLimit(resp *http.Response) bool {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+	resp.Body = io.NopCloser(strings.NewReader(string(body)))
+	if err != nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(string(body)), "secondary rate limit")
+}
@@ -1,156 +1,206 @@
 package github
 
 import (
-	"archive/tar"
-	"compress/gzip"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"os"
-	"path/filepath"
+	"net/url"
 	"strings"
-	"time"
+
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/archive"
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/cache"
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/config"
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/httpclient"
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/progress"
 )
 
 const (
-	GitHubAPIURL   = "https://api.github.com"
-	RepoOwner      = "DevOpsBeerer"
-	RepoName       = "playground-scenarios-charts"
-	RequestTimeout = 30 * time.Second
+	GitHubAPIURL = "https://api.github.com"
 )
 
 // Downloader handles downloading Helm charts from GitHub
 type Downloader struct {
 	httpClient *http.Client
+	repoOwner  string
+	repoName   string
+	ref        string
+	token      string
+	cache      *cache.Cache
+	progress   progress.Reporter
 }
 
-// NewDownloader creates a new GitHub downloader
-func NewDownloader() *Downloader {
-	return &Downloader{
-		httpClient: &http.Client{
-			Timeout: RequestTimeout,
-		},
-	}
+// Download describes what DownloadChart fetched
+type Download struct {
+	// Repo identifies the repository in the chart cache, e.g.
+	// github.com/DevOpsBeerer/playground-scenarios-charts
+	Repo string
+	// Ref is the branch, tag or SHA that was requested
+	Ref string
+	// Commit is the commit SHA the ref resolved to, empty when GitHub
+	// didn't report it
+	Commit string
 }
 
-// DownloadChart downloads a specific scenario chart from GitHub
-func (d *Downloader) DownloadChart(scenarioID, destPath string) error {
-	fmt.Printf("📥 Downloading chart for scenario: %s\n", scenarioID)
+// NewDownloader creates a new GitHub downloader for the configured chart source and ref
+func NewDownloader(cfg *config.Config) (*Downloader, error) {
+	return NewRepoDownloader(cfg.ChartSource, cfg.ChartRef, cfg)
+}
 
-	// Download the entire repository as a tarball
-	tarballURL := fmt.Sprintf("https://github.com/%s/%s/archive/refs/heads/main.tar.gz", RepoOwner, RepoName)
+// NewRepoDownloader creates a GitHub downloader for the given repository URL
+// and ref (branch, tag or commit SHA); an empty ref uses the configured one
+func NewRepoDownloader(repoURL, ref string, cfg *config.Config) (*Downloader, error) {
+	if ref == "" {
+		ref = cfg.ChartRef
+	}
 
-	// Download tarball
-	resp, err := d.httpClient.Get(tarballURL)
+	owner, name, err := ParseRepoURL(repoURL)
 	if err != nil {
-		return fmt.Errorf("failed to download repository: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to download repository: HTTP %d", resp.StatusCode)
+	httpClient, err := httpclient.New(cfg, cfg.Timeouts.Download)
+	if err != nil {
+		return nil, err
 	}
 
-	// Extract the specific scenario directory
-	if err := d.extractScenario(resp.Body, scenarioID, destPath); err != nil {
-		return fmt.Errorf("failed to extract scenario: %w", err)
+	return &Downloader{
+		httpClient: httpClient,
+		repoOwner:  owner,
+		repoName:   name,
+		ref:        ref,
+		token:      cfg.GitHubToken,
+		cache:      cache.New(),
+		progress:   progress.New(cfg),
+	}, nil
+}
+
+// ParseRepoURL extracts the owner and repository name from a GitHub URL
+// such as https://github.com/DevOpsBeerer/playground-scenarios-charts
+func ParseRepoURL(repoURL string) (string, string, error) {
+	u, err := url.Parse(repoURL)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid repository URL '%s': %w", repoURL, err)
 	}
 
-	fmt.Printf("✅ Chart downloaded successfully to %s\n", destPath)
-	return nil
+	parts := strings.Split(strings.Trim(strings.TrimSuffix(u.Path, ".git"), "/"), "/")
+	if u.Host != "github.com" || len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("'%s' is not a GitHub repository URL", repoURL)
+	}
+
+	return parts[0], parts[1], nil
 }
 
-// extractScenario extracts only the specified scenario from the tarball
-func (d *Downloader) extractScenario(reader io.Reader, scenarioID, destPath string) error {
-	// Create destination directory
-	if err := os.MkdirAll(destPath, 0755); err != nil {
-		return fmt.Errorf("failed to create destination directory: %w", err)
-	}
+// DownloadChart downloads a specific scenario chart from GitHub
+// chartDir is the directory holding the chart inside the repository
+func (d *Downloader) DownloadChart(chartDir, destPath string) (*Download, error) {
+	fmt.Printf("📥 Downloading chart: %s (ref %s)\n", chartDir, d.ref)
 
-	// Create gzip reader
-	gzipReader, err := gzip.NewReader(reader)
+	tarball, err := d.fetchTarball()
 	if err != nil {
-		return fmt.Errorf("failed to create gzip reader: %w", err)
+		return nil, err
 	}
-	defer gzipReader.Close()
+	defer tarball.Close()
 
-	// Create tar reader
-	tarReader := tar.NewReader(gzipReader)
+	download := &Download{Repo: d.repoKey(), Ref: d.ref}
 
-	// Expected prefix in the tarball (GitHub adds repo name prefix)
-	expectedPrefix := fmt.Sprintf("%s-main/%s/", RepoName, scenarioID)
+	// Extract the specific scenario directory
+	if err := d.extractScenario(tarball, chartDir, destPath, download); err != nil {
+		return nil, fmt.Errorf("failed to extract scenario: %w", err)
+	}
 
-	// Extract files
-	for {
-		header, err := tarReader.Next()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return fmt.Errorf("failed to read tar entry: %w", err)
-		}
+	if download.Commit != "" {
+		fmt.Printf("✅ Chart downloaded successfully to %s (commit %s)\n", destPath, download.Commit)
+	} else {
+		fmt.Printf("✅ Chart downloaded successfully to %s\n", destPath)
+	}
+	return download, nil
+}
 
-		// Check if this file belongs to our scenario
-		if !strings.HasPrefix(header.Name, expectedPrefix) {
-			continue
-		}
+// fetchTarball returns the repository tarball for the ref, revalidating
+// the cached copy with GitHub
+// Commit SHAs never change and are served from the cache without asking
+func (d *Downloader) fetchTarball() (io.ReadCloser, error) {
+	// Download the entire repository as a tarball
+	// GitHub serves archives for branches, tags and commit SHAs alike
+	tarballURL := fmt.Sprintf("https://github.com/%s/%s/archive/%s.tar.gz", d.repoOwner, d.repoName, url.PathEscape(d.ref))
 
-		// Calculate relative path within the scenario
-		relativePath := strings.TrimPrefix(header.Name, expectedPrefix)
-		if relativePath == "" {
-			continue // Skip the directory itself
-		}
+	tarball, err := d.cache.Fetch(d.repoKey(), d.ref, tarballURL, cache.IsCommitSHA(d.ref), d.do, d.progress)
+	if errors.Is(err, cache.ErrNotFound) {
+		return nil, fmt.Errorf("ref '%s' not found in %s/%s", d.ref, d.repoOwner, d.repoName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to download repository: %w", err)
+	}
+	return tarball, nil
+}
 
-		targetPath := filepath.Join(destPath, relativePath)
-
-		switch header.Typeflag {
-		case tar.TypeDir:
-			// Create directory
-			if err := os.MkdirAll(targetPath, os.FileMode(header.Mode)); err != nil {
-				return fmt.Errorf("failed to create directory %s: %w", targetPath, err)
-			}
-
-		case tar.TypeReg:
-			// Create file
-			if err := d.extractFile(tarReader, targetPath, os.FileMode(header.Mode)); err != nil {
-				return fmt.Errorf("failed to extract file %s: %w", targetPath, err)
-			}
-			fmt.Printf("📄 Extracted: %s\n", relativePath)
-		}
+// ResolveRef returns the commit SHA a branch, tag or SHA points to
+func (d *Downloader) ResolveRef() (string, error) {
+	if cache.IsCommitSHA(d.ref) {
+		return d.ref, nil
 	}
 
-	return nil
-}
+	url := fmt.Sprintf("%s/repos/%s/%s/commits/%s", GitHubAPIURL, d.repoOwner, d.repoName, url.PathEscape(d.ref))
 
-// extractFile extracts a single file from the tar reader
-func (d *Downloader) extractFile(tarReader *tar.Reader, targetPath string, mode os.FileMode) error {
-	// Ensure directory exists
-	dir := filepath.Dir(targetPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory for file: %w", err)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
 	}
+	// Ask for the bare SHA instead of the whole commit
+	req.Header.Set("Accept", "application/vnd.github.sha")
 
-	// Create file
-	file, err := os.OpenFile(targetPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	resp, err := d.do(req)
 	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
+		return "", fmt.Errorf("failed to resolve ref '%s': %w", d.ref, err)
 	}
-	defer file.Close()
+	defer resp.Body.Close()
 
-	// Copy content
-	if _, err := io.Copy(file, tarReader); err != nil {
-		return fmt.Errorf("failed to write file content: %w", err)
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusUnprocessableEntity {
+		return "", fmt.Errorf("ref '%s' not found in %s/%s", d.ref, d.repoOwner, d.repoName)
 	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to resolve ref '%s': HTTP %d", d.ref, resp.StatusCode)
+	}
+
+	sha, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve ref '%s': %w", d.ref, err)
+	}
+	return strings.TrimSpace(string(sha)), nil
+}
+
+// repoKey identifies the repository in the chart cache
+func (d *Downloader) repoKey() string {
+	return fmt.Sprintf("github.com/%s/%s", d.repoOwner, d.repoName)
+}
 
-	return nil
+// extractScenario extracts only the specified scenario from the tarball
+// and records the commit GitHub stores in the archive's global header
+func (d *Downloader) extractScenario(reader io.Reader, chartDir, destPath string, download *Download) error {
+	// GitHub puts everything under a "<repo>-<ref>/" directory
+	return archive.ExtractTarGz(reader, destPath, archive.Options{
+		StripComponents: 1,
+		Dir:             chartDir,
+		OnFile:          d.progress.File,
+		OnGlobalHeader: func(records map[string]string) {
+			download.Commit = records["comment"]
+		},
+	})
 }
 
 // ListScenarios lists all available scenarios from the repository
 func (d *Downloader) ListScenarios() ([]string, error) {
-	url := fmt.Sprintf("%s/repos/%s/%s/contents", GitHubAPIURL, RepoOwner, RepoName)
+	url := fmt.Sprintf("%s/repos/%s/%s/contents", GitHubAPIURL, d.repoOwner, d.repoName)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
 
-	resp, err := d.httpClient.Get(url)
+	resp, err := d.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch repository contents: %w", err)
 	}
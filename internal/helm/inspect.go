@@ -0,0 +1,88 @@
+package helm
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart/loader"
+)
+
+// ChartDetails describes what a chart exposes to its users
+type ChartDetails struct {
+	Name        string
+	Version     string
+	AppVersion  string
+	Description string
+	README      string
+	Values      []ValueDefault
+}
+
+// ValueDefault is a single chart value with its default setting
+type ValueDefault struct {
+	Key     string
+	Default string
+}
+
+// InspectChart loads the chart at chartPath and returns its details
+func InspectChart(chartPath string) (*ChartDetails, error) {
+	chart, err := loader.Load(chartPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chart: %w", err)
+	}
+
+	details := &ChartDetails{
+		Name:        chart.Metadata.Name,
+		Version:     chart.Metadata.Version,
+		AppVersion:  chart.Metadata.AppVersion,
+		Description: chart.Metadata.Description,
+		Values:      FlattenValues(chart.Values),
+	}
+
+	for _, file := range chart.Files {
+		if strings.EqualFold(file.Name, "README.md") {
+			details.README = string(file.Data)
+			break
+		}
+	}
+
+	return details, nil
+}
+
+// FlattenValues turns nested chart values into dotted keys, sorted by key
+// Lists and scalars are rendered as compact JSON
+func FlattenValues(values map[string]interface{}) []ValueDefault {
+	var flat []ValueDefault
+	flattenInto(&flat, "", values)
+
+	sort.Slice(flat, func(i, j int) bool {
+		return flat[i].Key < flat[j].Key
+	})
+	return flat
+}
+
+func flattenInto(flat *[]ValueDefault, prefix string, values map[string]interface{}) {
+	for key, value := range values {
+		fullKey := key
+		if prefix != "" {
+			fullKey = prefix + "." + key
+		}
+
+		if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+			flattenInto(flat, fullKey, nested)
+			continue
+		}
+
+		*flat = append(*flat, ValueDefault{Key: fullKey, Default: formatValue(value)})
+	}
+}
+
+// formatValue renders a value on a single line
+func formatValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
@@ -3,35 +3,39 @@ package helm
 import (
 	"fmt"
 	"os"
-	"time"
 
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/config"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart/loader"
 	"helm.sh/helm/v3/pkg/cli"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 )
 
-const ()
-
 // Manager handles Helm operations
 type Manager struct {
-	settings  *cli.EnvSettings
-	namespace string
+	settings   *cli.EnvSettings
+	namespace  string
+	kubeconfig string
+	timeouts   config.Timeouts
 }
 
 // NewManager creates a new Helm manager
-func NewManager(namespace string) *Manager {
+func NewManager(namespace string, cfg *config.Config) *Manager {
 	settings := cli.New()
 	settings.SetNamespace(namespace)
+	settings.KubeConfig = cfg.Kubeconfig
 
 	return &Manager{
-		settings:  settings,
-		namespace: namespace,
+		settings:   settings,
+		namespace:  namespace,
+		kubeconfig: cfg.Kubeconfig,
+		timeouts:   cfg.Timeouts,
 	}
 }
 
 // InstallScenario installs a scenario using Helm
-func (m *Manager) InstallScenario(scenarioID, chartPath string) error {
+// values override the chart defaults and may be nil
+func (m *Manager) InstallScenario(scenarioID, chartPath string, values map[string]interface{}) error {
 	fmt.Printf("🔍 Checking for existing scenario deployment...\n")
 
 	// Remove existing deployment if it exists
@@ -43,7 +47,7 @@ func (m *Manager) InstallScenario(scenarioID, chartPath string) error {
 	fmt.Printf("📦 Installing scenario via Helm...\n")
 
 	// Install the chart
-	if err := m.installChart(scenarioID, chartPath); err != nil {
+	if err := m.installChart(scenarioID, chartPath, values); err != nil {
 		return fmt.Errorf("failed to install chart: %w", err)
 	}
 
@@ -56,7 +60,7 @@ func (m *Manager) UninstallScenario(scenarioID string) error {
 
 	// Initialize Helm action configuration
 	if err := actionConfig.Init(
-		&genericclioptions.ConfigFlags{Namespace: &m.namespace},
+		&genericclioptions.ConfigFlags{Namespace: &m.namespace, KubeConfig: &m.kubeconfig},
 		m.namespace,
 		os.Getenv("HELM_DRIVER"),
 		func(format string, v ...interface{}) {
@@ -69,7 +73,7 @@ func (m *Manager) UninstallScenario(scenarioID string) error {
 	// Create uninstall action
 	uninstall := action.NewUninstall(actionConfig)
 	uninstall.Wait = true
-	uninstall.Timeout = 5 * time.Minute
+	uninstall.Timeout = m.timeouts.Uninstall
 
 	// Check if release exists
 	_, err := actionConfig.Releases.Last(scenarioID)
@@ -90,12 +94,12 @@ func (m *Manager) UninstallScenario(scenarioID string) error {
 }
 
 // installChart installs the downloaded Helm chart
-func (m *Manager) installChart(scenarioID, chartPath string) error {
+func (m *Manager) installChart(scenarioID, chartPath string, values map[string]interface{}) error {
 	actionConfig := new(action.Configuration)
 
 	// Initialize Helm action configuration
 	if err := actionConfig.Init(
-		&genericclioptions.ConfigFlags{Namespace: &m.namespace},
+		&genericclioptions.ConfigFlags{Namespace: &m.namespace, KubeConfig: &m.kubeconfig},
 		m.namespace,
 		os.Getenv("HELM_DRIVER"),
 		func(format string, v ...interface{}) {
@@ -116,11 +120,14 @@ func (m *Manager) installChart(scenarioID, chartPath string) error {
 	install.ReleaseName = scenarioID
 	install.Namespace = m.namespace
 	install.Wait = true
-	install.Timeout = 10 * time.Minute
+	install.Timeout = m.timeouts.Install
 	install.CreateNamespace = true
 
 	// Install the chart
-	_, err = install.Run(chart, map[string]interface{}{})
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+	_, err = install.Run(chart, values)
 	if err != nil {
 		return fmt.Errorf("failed to install chart: %w", err)
 	}
@@ -134,7 +141,7 @@ func (m *Manager) GetScenarioStatus(scenarioID string) (bool, string, error) {
 
 	// Initialize Helm action configuration
 	if err := actionConfig.Init(
-		&genericclioptions.ConfigFlags{Namespace: &m.namespace},
+		&genericclioptions.ConfigFlags{Namespace: &m.namespace, KubeConfig: &m.kubeconfig},
 		m.namespace,
 		os.Getenv("HELM_DRIVER"),
 		func(format string, v ...interface{}) {
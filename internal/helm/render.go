@@ -0,0 +1,96 @@
+package helm
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// RenderedObject is a Kubernetes object produced by a chart template
+type RenderedObject struct {
+	Template string
+	Object   *unstructured.Unstructured
+}
+
+// RenderChart renders the chart the way installing it as releaseName into
+// namespace would, without contacting a cluster
+// Objects are returned in template order
+func RenderChart(chrt *chart.Chart, releaseName, namespace string, values map[string]interface{}) ([]RenderedObject, error) {
+	options := chartutil.ReleaseOptions{
+		Name:      releaseName,
+		Namespace: namespace,
+		Revision:  1,
+		IsInstall: true,
+	}
+
+	renderValues, err := chartutil.ToRenderValues(chrt, values, options, chartutil.DefaultCapabilities)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare values: %w", err)
+	}
+
+	rendered, err := engine.Render(chrt, renderValues)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render chart: %w", err)
+	}
+
+	names := make([]string, 0, len(rendered))
+	for name := range rendered {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var objects []RenderedObject
+	for _, name := range names {
+		// Partials and NOTES.txt don't produce objects
+		base := path.Base(name)
+		if strings.HasPrefix(base, "_") || !strings.HasSuffix(base, ".yaml") && !strings.HasSuffix(base, ".yml") {
+			continue
+		}
+
+		decoded, err := decodeObjects(rendered[name])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		for _, object := range decoded {
+			objects = append(objects, RenderedObject{Template: name, Object: object})
+		}
+	}
+
+	return objects, nil
+}
+
+// decodeObjects parses every non-empty YAML document of a rendered template
+func decodeObjects(manifest string) ([]*unstructured.Unstructured, error) {
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(strings.NewReader(manifest)))
+
+	var objects []*unstructured.Unstructured
+	for {
+		document, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return objects, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read manifest: %w", err)
+		}
+
+		object := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal(document, &object.Object); err != nil {
+			return nil, fmt.Errorf("failed to parse manifest: %w", err)
+		}
+		if len(object.Object) == 0 {
+			continue
+		}
+		objects = append(objects, object)
+	}
+}
@@ -0,0 +1,35 @@
+package helm
+
+import (
+	"fmt"
+
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/cli/values"
+	"helm.sh/helm/v3/pkg/getter"
+)
+
+// ValueOptions are Helm-style value overrides given on the command line
+type ValueOptions struct {
+	// ValueFiles are YAML files or URLs (-f/--values)
+	ValueFiles []string
+	// Values are key=value pairs with type inference (--set)
+	Values []string
+	// StringValues are key=value pairs kept as strings (--set-string)
+	StringValues []string
+}
+
+// Merge combines the overrides the way 'helm install' does: value files in
+// order, then --set, then --set-string
+func (o ValueOptions) Merge() (map[string]interface{}, error) {
+	options := values.Options{
+		ValueFiles:   o.ValueFiles,
+		Values:       o.Values,
+		StringValues: o.StringValues,
+	}
+
+	merged, err := options.MergeValues(getter.All(cli.New()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge values: %w", err)
+	}
+	return merged, nil
+}
@@ -0,0 +1,110 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/config"
+)
+
+// DefaultUserAgent is sent when http.userAgent isn't set, the CLI adds its
+// version at startup
+var DefaultUserAgent = "dbeerer"
+
+// New returns an HTTP client for outbound requests bounded by timeout
+// It goes through the proxy of HTTP_PROXY, HTTPS_PROXY and NO_PROXY,
+// trusts the configured CA bundle on top of the system roots and sends
+// the configured user agent
+func New(cfg *config.Config, timeout time.Duration) (*http.Client, error) {
+	tlsConfig, err := tlsConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+	transport.TLSClientConfig = tlsConfig
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &userAgentTransport{
+			userAgent: UserAgent(cfg),
+			base:      transport,
+		},
+	}, nil
+}
+
+// UserAgent returns the configured user agent or the default one
+func UserAgent(cfg *config.Config) string {
+	if cfg.HTTP.UserAgent != "" {
+		return cfg.HTTP.UserAgent
+	}
+	return DefaultUserAgent
+}
+
+// GitEnv returns the environment variables that give git the same proxy,
+// CA bundle, TLS verification and user agent settings, to append to
+// os.Environ()
+func GitEnv(cfg *config.Config) []string {
+	env := []string{"GIT_HTTP_USER_AGENT=" + UserAgent(cfg)}
+
+	if cfg.HTTP.CABundle != "" {
+		env = append(env, "GIT_SSL_CAINFO="+cfg.HTTP.CABundle)
+	}
+	if cfg.HTTP.InsecureSkipVerify {
+		env = append(env, "GIT_SSL_NO_VERIFY=true")
+	}
+
+	// git ignores the upper-case HTTP_PROXY that Go honours
+	if proxy := os.Getenv("HTTP_PROXY"); proxy != "" && os.Getenv("http_proxy") == "" {
+		env = append(env, "http_proxy="+proxy)
+	}
+
+	return env
+}
+
+// tlsConfig returns the TLS settings of outbound connections
+func tlsConfig(cfg *config.Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: cfg.HTTP.InsecureSkipVerify,
+	}
+
+	if cfg.HTTP.CABundle == "" {
+		return tlsConfig, nil
+	}
+
+	pem, err := os.ReadFile(cfg.HTTP.CABundle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+	}
+
+	roots, err := x509.SystemCertPool()
+	if err != nil {
+		roots = x509.NewCertPool()
+	}
+	if !roots.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in CA bundle %s", cfg.HTTP.CABundle)
+	}
+	tlsConfig.RootCAs = roots
+
+	return tlsConfig, nil
+}
+
+// userAgentTransport sets the User-Agent of requests that don't have one
+type userAgentTransport struct {
+	userAgent string
+	base      http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("User-Agent") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("User-Agent", t.userAgent)
+	}
+	return t.base.RoundTrip(req)
+}
@@ -6,21 +6,31 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/config"
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/httpclient"
 )
 
 const (
-	PlaygroundRepoURL = "https://github.com/DevOpsBeerer/playground.git"
-	TempDirPrefix     = "devopsbeerer-infra-"
+	TempDirPrefix = "devopsbeerer-infra-"
 )
 
 // Manager handles infrastructure operations
 type Manager struct {
 	workDir string
+	config  *config.Config
 }
 
 // NewManager creates a new infrastructure manager
-func NewManager() *Manager {
-	return &Manager{}
+func NewManager(cfg *config.Config) *Manager {
+	return &Manager{config: cfg}
+}
+
+// kubeCommand builds a kubectl or helm command targeting the configured cluster
+func (m *Manager) kubeCommand(name string, args ...string) *exec.Cmd {
+	cmd := exec.Command(name, args...)
+	cmd.Env = append(os.Environ(), "KUBECONFIG="+m.config.Kubeconfig)
+	return cmd
 }
 
 // DeployInfrastructure clones the playground repo and runs setup scripts
@@ -68,7 +78,8 @@ func (m *Manager) cloneRepository() error {
 
 	repoDir := filepath.Join(m.workDir, "playground")
 
-	cmd := exec.Command("git", "clone", PlaygroundRepoURL, repoDir)
+	cmd := exec.Command("git", "clone", m.config.PlaygroundRepoURL, repoDir)
+	cmd.Env = append(os.Environ(), httpclient.GitEnv(m.config)...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
@@ -152,7 +163,7 @@ func (m *Manager) CheckInfrastructure() (*InfrastructureStatus, error) {
 	status.KubectlAvailable = true
 
 	// Check K3s cluster
-	if err := exec.Command("kubectl", "cluster-info").Run(); err != nil {
+	if err := m.kubeCommand("kubectl", "cluster-info").Run(); err != nil {
 		status.ClusterRunning = false
 	} else {
 		status.ClusterRunning = true
@@ -215,7 +226,7 @@ func (m *Manager) isComponentHealthy(helmRelease, namespace, selector string) bo
 }
 
 func (m *Manager) isHelmReleaseDeployed(releaseName, namespace string) bool {
-	cmd := exec.Command("helm", "status", releaseName, "-n", namespace, "-o", "json")
+	cmd := m.kubeCommand("helm", "status", releaseName, "-n", namespace, "-o", "json")
 	output, err := cmd.Output()
 	if err != nil {
 		return false
@@ -241,7 +252,7 @@ func (m *Manager) arePodsReady(namespace, selector string) bool {
 		args = append(args, "-l", selector)
 	}
 
-	cmd := exec.Command("kubectl", args...)
+	cmd := m.kubeCommand("kubectl", args...)
 	output, err := cmd.Output()
 	if err != nil {
 		return false
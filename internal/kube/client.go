@@ -0,0 +1,22 @@
+package kube
+
+import (
+	"fmt"
+
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/config"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// RESTConfig builds the REST config for the configured kubeconfig
+// Every request is bounded by the request timeout
+func RESTConfig(cfg *config.Config) (*rest.Config, error) {
+	// Use the current context in kubeconfig
+	restConfig, err := clientcmd.BuildConfigFromFlags("", cfg.Kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build kubeconfig: %w", err)
+	}
+	restConfig.Timeout = cfg.Timeouts.Request
+
+	return restConfig, nil
+}
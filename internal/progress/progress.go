@@ -0,0 +1,175 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/config"
+	"golang.org/x/term"
+)
+
+const (
+	// barWidth is the number of cells of the bar drawn on terminals
+	barWidth = 30
+	// barInterval throttles redrawing the bar
+	barInterval = 100 * time.Millisecond
+	// lineInterval spaces progress lines when the output isn't a terminal
+	lineInterval = 5 * time.Second
+)
+
+// Reporter follows a download and the files extracted from it
+type Reporter interface {
+	// Start begins a transfer of total bytes, -1 when the size is unknown
+	Start(name string, total int64)
+	// Add records n more transferred bytes
+	Add(n int64)
+	// Finish ends the transfer, err is the error that interrupted it
+	Finish(err error)
+	// File records a file extracted from the download
+	File(name string)
+}
+
+// New returns the reporter for the configuration, writing to stdout
+func New(cfg *config.Config) Reporter {
+	return NewWriter(os.Stdout, cfg.Quiet)
+}
+
+// NewWriter returns a reporter drawing a bar when out is a terminal and
+// printing a line every few seconds otherwise. Quiet reporters don't
+// list extracted files
+func NewWriter(out *os.File, quiet bool) Reporter {
+	return &reporter{
+		out:   out,
+		tty:   term.IsTerminal(int(out.Fd())),
+		quiet: quiet,
+	}
+}
+
+// Reader wraps r so that reads are reported to reporter
+func Reader(r io.Reader, reporter Reporter) io.Reader {
+	return &reader{Reader: r, reporter: reporter}
+}
+
+// reader reports the bytes read through it
+type reader struct {
+	io.Reader
+	reporter Reporter
+}
+
+// Read implements io.Reader
+func (r *reader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.reporter.Add(int64(n))
+	return n, err
+}
+
+// reporter writes progress to a terminal or a log
+type reporter struct {
+	out   io.Writer
+	tty   bool
+	quiet bool
+
+	name      string
+	total     int64
+	done      int64
+	started   time.Time
+	lastPrint time.Time
+}
+
+// Start implements Reporter
+func (r *reporter) Start(name string, total int64) {
+	now := time.Now()
+	r.name = name
+	r.total = total
+	r.done = 0
+	r.started = now
+	r.lastPrint = now
+}
+
+// Add implements Reporter
+func (r *reporter) Add(n int64) {
+	r.done += n
+
+	now := time.Now()
+	switch {
+	case r.tty && now.Sub(r.lastPrint) >= barInterval:
+		fmt.Fprintf(r.out, "\r\033[K⏳ %s %s", r.name, r.bar(now))
+	case !r.tty && now.Sub(r.lastPrint) >= lineInterval:
+		fmt.Fprintf(r.out, "⏳ %s: %s\n", r.name, r.status(now))
+	default:
+		return
+	}
+	r.lastPrint = now
+}
+
+// Finish implements Reporter
+func (r *reporter) Finish(err error) {
+	if r.tty {
+		fmt.Fprint(r.out, "\r\033[K")
+	}
+	if err != nil {
+		return
+	}
+
+	elapsed := time.Since(r.started)
+	fmt.Fprintf(r.out, "📥 Downloaded %s: %s in %s (%s/s)\n",
+		r.name, FormatBytes(r.done), elapsed.Round(100*time.Millisecond), FormatBytes(rate(r.done, elapsed)))
+}
+
+// File implements Reporter
+func (r *reporter) File(name string) {
+	if !r.quiet {
+		fmt.Fprintf(r.out, "📄 Extracted: %s\n", name)
+	}
+}
+
+// bar renders the progress bar, without one when the size is unknown
+func (r *reporter) bar(now time.Time) string {
+	if r.total <= 0 {
+		return r.status(now)
+	}
+
+	filled := int(int64(barWidth) * min(r.done, r.total) / r.total)
+	return fmt.Sprintf("[%s%s] %s", strings.Repeat("█", filled), strings.Repeat("░", barWidth-filled), r.status(now))
+}
+
+// status describes the transferred bytes, the rate and the remaining time
+func (r *reporter) status(now time.Time) string {
+	bytesPerSecond := rate(r.done, now.Sub(r.started))
+	if r.total <= 0 {
+		return fmt.Sprintf("%s, %s/s", FormatBytes(r.done), FormatBytes(bytesPerSecond))
+	}
+
+	eta := "unknown"
+	if bytesPerSecond > 0 {
+		eta = (time.Duration(max(r.total-r.done, 0)/bytesPerSecond) * time.Second).String()
+	}
+	return fmt.Sprintf("%s of %s (%d%%), %s/s, ETA %s",
+		FormatBytes(r.done), FormatBytes(r.total), 100*min(r.done, r.total)/r.total, FormatBytes(bytesPerSecond), eta)
+}
+
+// rate returns the bytes transferred per second
+func rate(bytes int64, elapsed time.Duration) int64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return int64(float64(bytes) / elapsed.Seconds())
+}
+
+// FormatBytes formats a size with a binary unit, e.g. 1.5 MiB
+func FormatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,262 @@
+package scenarios
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/sources"
+	"github.com/DevOpsBeerer/dbeerer-cli/pkg/apis/devopsbeerer/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Kinds of catalogue changes
+const (
+	ChangeNew     = "new"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+// CatalogChange describes how a scenario upstream differs from the one
+// registered in the cluster
+type CatalogChange struct {
+	ID   string
+	Kind string
+	// Fields lists the changed fields of ChangeChanged scenarios
+	Fields []FieldChange
+	// Definition is the upstream definition, nil for removed scenarios
+	Definition *v1alpha1.ScenarioDefinition
+}
+
+// FieldChange is a field whose value differs between cluster and upstream
+type FieldChange struct {
+	Field    string
+	Cluster  string
+	Upstream string
+}
+
+// FetchMetadataCatalog downloads the upstream catalogue from a
+// metadata.json listing scenarios, either as an array or under a
+// "scenarios" key
+func (m *Manager) FetchMetadataCatalog(metadataURL string) ([]*v1alpha1.ScenarioDefinition, error) {
+	fmt.Printf("📥 Fetching catalogue: %s\n", metadataURL)
+
+	resp, err := m.httpClient.Get(metadataURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch catalogue: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch catalogue: HTTP %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch catalogue: %w", err)
+	}
+
+	var scenarios []Scenario
+	if err := json.Unmarshal(data, &scenarios); err != nil {
+		var wrapped struct {
+			Scenarios []Scenario `json:"scenarios"`
+		}
+		if err := json.Unmarshal(data, &wrapped); err != nil {
+			return nil, fmt.Errorf("failed to parse catalogue: %w", err)
+		}
+		scenarios = wrapped.Scenarios
+	}
+
+	definitions := make([]*v1alpha1.ScenarioDefinition, 0, len(scenarios))
+	for _, scenario := range scenarios {
+		definition := m.definitionFromScenario(scenario)
+		if err := ValidateDefinition(definition); err != nil {
+			return nil, fmt.Errorf("catalogue: %w", err)
+		}
+		definitions = append(definitions, definition)
+	}
+
+	return definitions, nil
+}
+
+// FetchChartCatalog builds the upstream catalogue from the DefinitionFile
+// next to every chart of the configured chart source. Charts without one
+// are skipped
+func (m *Manager) FetchChartCatalog() ([]*v1alpha1.ScenarioDefinition, error) {
+	source, err := sources.ForLink(m.config.ChartSource, m.config)
+	if err != nil {
+		return nil, err
+	}
+
+	dirs, err := source.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list charts: %w", err)
+	}
+
+	workDir, err := os.MkdirTemp("", "dbeerer-catalog-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	var definitions []*v1alpha1.ScenarioDefinition
+	for _, dir := range dirs {
+		chartPath := filepath.Join(workDir, dir)
+		if _, err := source.Fetch(dir, "", chartPath); err != nil {
+			return nil, err
+		}
+
+		path := filepath.Join(chartPath, DefinitionFile)
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			fmt.Printf("⚠️  Skipping %s: no %s\n", dir, DefinitionFile)
+			continue
+		}
+
+		loaded, err := LoadDefinitions(path)
+		if err != nil {
+			return nil, err
+		}
+		definitions = append(definitions, loaded...)
+	}
+
+	return definitions, nil
+}
+
+// DiffCatalog compares the scenarios registered in the cluster with an
+// upstream catalogue, sorted by scenario ID
+func (m *Manager) DiffCatalog(upstream []*v1alpha1.ScenarioDefinition) ([]CatalogChange, error) {
+	installed, err := m.ListScenarios()
+	if err != nil {
+		return nil, err
+	}
+
+	cluster := make(map[string]Scenario, len(installed))
+	for _, scenario := range installed {
+		cluster[scenario.ID] = scenario
+	}
+
+	var changes []CatalogChange
+	seen := make(map[string]bool, len(upstream))
+	for _, definition := range upstream {
+		id := definition.Spec.ID
+		seen[id] = true
+
+		current, ok := cluster[id]
+		if !ok {
+			changes = append(changes, CatalogChange{ID: id, Kind: ChangeNew, Definition: definition})
+			continue
+		}
+
+		if fields := diffScenarios(current, scenarioFromDefinition(definition)); len(fields) > 0 {
+			changes = append(changes, CatalogChange{ID: id, Kind: ChangeChanged, Fields: fields, Definition: definition})
+		}
+	}
+
+	for id := range cluster {
+		if !seen[id] {
+			changes = append(changes, CatalogChange{ID: id, Kind: ChangeRemoved})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].ID < changes[j].ID
+	})
+	return changes, nil
+}
+
+// definitionFromScenario turns a catalogue entry into a ScenarioDefinition
+// Entries without a chart link use the configured chart source
+func (m *Manager) definitionFromScenario(scenario Scenario) *v1alpha1.ScenarioDefinition {
+	link := scenario.HelmChart.Link
+	if link == "" {
+		link = m.config.ChartSource
+	}
+
+	return &v1alpha1.ScenarioDefinition{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "ScenarioDefinition",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: scenario.ID,
+		},
+		Spec: v1alpha1.ScenarioDefinitionSpec{
+			ID:          scenario.ID,
+			Name:        scenario.Name,
+			Description: scenario.Description,
+			Tags:        scenario.Tags,
+			Features:    scenario.Features,
+			HelmChart: v1alpha1.HelmChartSpec{
+				Link:   link,
+				Dir:    scenario.HelmChart.Dir,
+				Ref:    scenario.HelmChart.Ref,
+				Digest: scenario.HelmChart.Digest,
+			},
+		},
+	}
+}
+
+// diffScenarios returns the fields that differ between two scenarios
+func diffScenarios(cluster, upstream Scenario) []FieldChange {
+	var changes []FieldChange
+	compare := func(field, clusterValue, upstreamValue string) {
+		if clusterValue != upstreamValue {
+			changes = append(changes, FieldChange{Field: field, Cluster: clusterValue, Upstream: upstreamValue})
+		}
+	}
+	// Tags and features are sets, their order doesn't matter
+	compareSet := func(field string, clusterItems, upstreamItems []string) {
+		if !sameItems(clusterItems, upstreamItems) {
+			compare(field, strings.Join(clusterItems, ", "), strings.Join(upstreamItems, ", "))
+		}
+	}
+
+	compare("name", cluster.Name, upstream.Name)
+	compare("description", cluster.Description, upstream.Description)
+	compareSet("tags", cluster.Tags, upstream.Tags)
+	compareSet("features", cluster.Features, upstream.Features)
+	compare("helmChart.link", cluster.HelmChart.Link, upstream.HelmChart.Link)
+	compare("helmChart.dir", cluster.ChartDir(), upstream.ChartDir())
+	compare("helmChart.ref", cluster.HelmChart.Ref, upstream.HelmChart.Ref)
+	compare("helmChart.digest", cluster.HelmChart.Digest, upstream.HelmChart.Digest)
+
+	return changes
+}
+
+// sameItems reports whether two lists hold the same items in any order
+func sameItems(a, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
+
+// ApplyCatalogChange brings the cluster in line with upstream for one
+// scenario: new and changed definitions are applied, removed ones deleted
+func (m *Manager) ApplyCatalogChange(change CatalogChange) error {
+	switch change.Kind {
+	case ChangeNew:
+		_, err := m.ApplyDefinition(change.Definition)
+		return err
+	case ChangeChanged:
+		// Update the registered object even when its name isn't the ID
+		existing, err := m.GetDefinition(change.ID)
+		if err != nil {
+			return err
+		}
+		definition := change.Definition.DeepCopy()
+		definition.Name = existing.Name
+		_, err = m.ApplyDefinition(definition)
+		return err
+	case ChangeRemoved:
+		return m.DeleteDefinition(change.ID)
+	}
+	return fmt.Errorf("unknown catalogue change '%s'", change.Kind)
+}
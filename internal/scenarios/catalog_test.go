@@ -0,0 +1,161 @@
+package scenarios
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/config"
+	"github.com/DevOpsBeerer/dbeerer-cli/pkg/apis/devopsbeerer/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+// newTestManager returns a Manager on a fake cluster holding definitions
+func newTestManager(t *testing.T, definitions ...*v1alpha1.ScenarioDefinition) *Manager {
+	t.Helper()
+
+	dynamicClient := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		v1alpha1.ScenarioDefinitionsResource: "ScenarioDefinitionList",
+	})
+	m := &Manager{client: v1alpha1.New(dynamicClient), config: &config.Config{}}
+
+	for _, definition := range definitions {
+		if _, err := m.client.ScenarioDefinitions().Create(context.TODO(), definition, metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return m
+}
+
+// testDefinition returns a valid definition of the scenario id
+func testDefinition(id string) *v1alpha1.ScenarioDefinition {
+	return &v1alpha1.ScenarioDefinition{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: "ScenarioDefinition"},
+		ObjectMeta: metav1.ObjectMeta{Name: id},
+		Spec: v1alpha1.ScenarioDefinitionSpec{
+			ID:       id,
+			Name:     id,
+			Tags:     []string{"oidc", "keycloak"},
+			Features: []string{"pkce", "refresh"},
+			HelmChart: v1alpha1.HelmChartSpec{
+				Link: "https://github.com/DevOpsBeerer/playground-scenarios-charts",
+			},
+		},
+	}
+}
+
+func TestDiffCatalog(t *testing.T) {
+	unchanged := testDefinition("unchanged")
+	changed := testDefinition("changed")
+	removed := testDefinition("removed")
+	m := newTestManager(t, unchanged, changed, removed)
+
+	reordered := testDefinition("unchanged")
+	reordered.Spec.Tags = []string{"keycloak", "oidc"}
+	reordered.Spec.Features = []string{"refresh", "pkce"}
+	upstreamChanged := testDefinition("changed")
+	upstreamChanged.Spec.HelmChart.Ref = "v2"
+	added := testDefinition("added")
+
+	changes, err := m.DiffCatalog([]*v1alpha1.ScenarioDefinition{reordered, upstreamChanged, added})
+	if err != nil {
+		t.Fatalf("DiffCatalog() error = %v", err)
+	}
+
+	want := []CatalogChange{
+		{ID: "added", Kind: ChangeNew, Definition: added},
+		{ID: "changed", Kind: ChangeChanged, Definition: upstreamChanged, Fields: []FieldChange{
+			{Field: "helmChart.ref", Cluster: "", Upstream: "v2"},
+		}},
+		{ID: "removed", Kind: ChangeRemoved},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("DiffCatalog() = %+v, want %+v", changes, want)
+	}
+}
+
+func TestDiffScenarios(t *testing.T) {
+	base := scenarioFromDefinition(testDefinition("oidc"))
+
+	tests := []struct {
+		name   string
+		modify func(*Scenario)
+		want   []FieldChange
+	}{
+		{
+			name:   "identical",
+			modify: func(s *Scenario) {},
+		},
+		{
+			name:   "tags in another order",
+			modify: func(s *Scenario) { s.Tags = []string{"keycloak", "oidc"} },
+		},
+		{
+			name:   "features in another order",
+			modify: func(s *Scenario) { s.Features = []string{"refresh", "pkce"} },
+		},
+		{
+			name:   "tag added",
+			modify: func(s *Scenario) { s.Tags = []string{"oidc", "keycloak", "saml"} },
+			want:   []FieldChange{{Field: "tags", Cluster: "oidc, keycloak", Upstream: "oidc, keycloak, saml"}},
+		},
+		{
+			name:   "feature replaced",
+			modify: func(s *Scenario) { s.Features = []string{"pkce", "logout"} },
+			want:   []FieldChange{{Field: "features", Cluster: "pkce, refresh", Upstream: "pkce, logout"}},
+		},
+		{
+			name:   "dir set to the default",
+			modify: func(s *Scenario) { s.HelmChart.Dir = "oidc" },
+		},
+		{
+			name:   "dir moved",
+			modify: func(s *Scenario) { s.HelmChart.Dir = "charts/oidc" },
+			want:   []FieldChange{{Field: "helmChart.dir", Cluster: "oidc", Upstream: "charts/oidc"}},
+		},
+		{
+			name: "several fields",
+			modify: func(s *Scenario) {
+				s.Name = "OIDC"
+				s.HelmChart.Digest = "sha256:abc"
+			},
+			want: []FieldChange{
+				{Field: "name", Cluster: "oidc", Upstream: "OIDC"},
+				{Field: "helmChart.digest", Cluster: "", Upstream: "sha256:abc"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upstream := scenarioFromDefinition(testDefinition("oidc"))
+			tt.modify(&upstream)
+
+			if got := diffScenarios(base, upstream); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffScenarios() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSameItems(t *testing.T) {
+	tests := []struct {
+		a, b []string
+		want bool
+	}{
+		{a: nil, b: nil, want: true},
+		{a: nil, b: []string{}, want: true},
+		{a: []string{"a", "b"}, b: []string{"b", "a"}, want: true},
+		{a: []string{"a", "a"}, b: []string{"a"}, want: false},
+		{a: []string{"a", "b"}, b: []string{"a", "c"}, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := sameItems(tt.a, tt.b); got != tt.want {
+			t.Errorf("sameItems(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package scenarios
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/DevOpsBeerer/dbeerer-cli/pkg/apis/devopsbeerer/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// DefinitionFile is the ScenarioDefinition shipped next to a scenario chart
+// It is left out of the chart digest so the definition can carry it
+const DefinitionFile = "scenario.yaml"
+
+// digestPattern matches the chart digests ScenarioDefinitions may pin
+var digestPattern = regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)
+
+// LoadDefinitions reads ScenarioDefinitions from a YAML file, or from
+// stdin when path is "-". A file may hold several documents
+func LoadDefinitions(path string) ([]*v1alpha1.ScenarioDefinition, error) {
+	var reader io.Reader
+	if path == "-" {
+		reader = os.Stdin
+	} else {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", path, err)
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	definitions, err := DecodeDefinitions(reader)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return definitions, nil
+}
+
+// DecodeDefinitions decodes and validates every YAML document in reader
+// Unknown fields are rejected so typos don't silently drop values
+func DecodeDefinitions(reader io.Reader) ([]*v1alpha1.ScenarioDefinition, error) {
+	yamlReader := utilyaml.NewYAMLReader(bufio.NewReader(reader))
+
+	var definitions []*v1alpha1.ScenarioDefinition
+	for index := 0; ; index++ {
+		document, err := yamlReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read document %d: %w", index+1, err)
+		}
+		if len(bytes.TrimSpace(document)) == 0 {
+			continue
+		}
+
+		definition := &v1alpha1.ScenarioDefinition{}
+		if err := yaml.UnmarshalStrict(document, definition); err != nil {
+			return nil, fmt.Errorf("document %d: %w", index+1, err)
+		}
+
+		if definition.Name == "" {
+			definition.Name = definition.Spec.ID
+		}
+
+		if err := ValidateDefinition(definition); err != nil {
+			return nil, fmt.Errorf("document %d: %w", index+1, err)
+		}
+		definitions = append(definitions, definition)
+	}
+
+	if len(definitions) == 0 {
+		return nil, fmt.Errorf("no ScenarioDefinition found")
+	}
+
+	return definitions, nil
+}
+
+// ValidateDefinition checks a ScenarioDefinition against the fields the CLI
+// and the operator rely on
+func ValidateDefinition(definition *v1alpha1.ScenarioDefinition) error {
+	var problems []string
+
+	if definition.APIVersion != v1alpha1.SchemeGroupVersion.String() {
+		problems = append(problems, fmt.Sprintf("apiVersion must be %s", v1alpha1.SchemeGroupVersion))
+	}
+	if definition.Kind != "ScenarioDefinition" {
+		problems = append(problems, "kind must be ScenarioDefinition")
+	}
+
+	// The ID ends up in namespace and release names
+	if definition.Spec.ID == "" {
+		problems = append(problems, "spec.id is required")
+	} else if errs := validation.IsDNS1123Label(definition.Spec.ID); len(errs) > 0 {
+		problems = append(problems, fmt.Sprintf("spec.id %s", strings.Join(errs, ", ")))
+	}
+	if errs := validation.IsDNS1123Subdomain(definition.Name); definition.Name != "" && len(errs) > 0 {
+		problems = append(problems, fmt.Sprintf("metadata.name %s", strings.Join(errs, ", ")))
+	}
+
+	if definition.Spec.Name == "" {
+		problems = append(problems, "spec.name is required")
+	}
+	if definition.Spec.HelmChart.Link == "" {
+		problems = append(problems, "spec.helmChart.link is required")
+	}
+	// The directory is joined to the chart source, it must stay inside it
+	if dir := definition.Spec.HelmChart.Dir; dir != "" && (filepath.IsAbs(dir) || !filepath.IsLocal(dir) ||
+		slices.Contains(strings.Split(filepath.ToSlash(dir), "/"), "..")) {
+		problems = append(problems, "spec.helmChart.dir must be a relative path inside the chart source")
+	}
+	if digest := definition.Spec.HelmChart.Digest; digest != "" && !digestPattern.MatchString(digest) {
+		problems = append(problems, "spec.helmChart.digest must look like sha256:<64 hex characters>")
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid ScenarioDefinition %q: %s", definition.Name, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// ApplyDefinition creates the ScenarioDefinition, or updates it when one
+// with the same name exists. It reports whether the object was created
+// Scenarios are looked up by spec.id, so an ID another definition already
+// uses is rejected
+func (m *Manager) ApplyDefinition(definition *v1alpha1.ScenarioDefinition) (bool, error) {
+	list, err := m.listDefinitions()
+	if err != nil {
+		return false, err
+	}
+	for _, other := range list.Items {
+		if other.Spec.ID == definition.Spec.ID && other.Name != definition.Name {
+			return false, fmt.Errorf("scenario ID '%s' is already used by scenario definition %s, apply it under that name or delete it first", definition.Spec.ID, other.Name)
+		}
+	}
+
+	definitions := m.client.ScenarioDefinitions()
+
+	existing, err := definitions.Get(context.TODO(), definition.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		if _, err := definitions.Create(context.TODO(), definition, metav1.CreateOptions{}); err != nil {
+			return false, fmt.Errorf("failed to create scenario definition %s: %w", definition.Name, err)
+		}
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get scenario definition %s: %w", definition.Name, err)
+	}
+
+	updated := existing.DeepCopy()
+	updated.Spec = definition.Spec
+	for key, value := range definition.Labels {
+		metav1.SetMetaDataLabel(&updated.ObjectMeta, key, value)
+	}
+	for key, value := range definition.Annotations {
+		metav1.SetMetaDataAnnotation(&updated.ObjectMeta, key, value)
+	}
+
+	if _, err := definitions.Update(context.TODO(), updated, metav1.UpdateOptions{}); err != nil {
+		return false, fmt.Errorf("failed to update scenario definition %s: %w", definition.Name, err)
+	}
+	return false, nil
+}
+
+// listDefinitions lists the ScenarioDefinitions, warning about and
+// skipping the ones that can't be decoded
+func (m *Manager) listDefinitions() (*v1alpha1.ScenarioDefinitionList, error) {
+	list, err := m.client.ScenarioDefinitions().List(context.TODO(), metav1.ListOptions{})
+	if err = warnDecodeErrors(err); err != nil {
+		return nil, fmt.Errorf("failed to list scenario definitions: %w", err)
+	}
+	return list, nil
+}
+
+// warnDecodeErrors prints the items a List couldn't decode to stderr and
+// returns any other error
+func warnDecodeErrors(err error) error {
+	var decodeErr *v1alpha1.DecodeError
+	if !errors.As(err, &decodeErr) {
+		return err
+	}
+
+	names := make([]string, 0, len(decodeErr.Items))
+	for name := range decodeErr.Items {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "Warning: failed to parse %s: %v\n", name, decodeErr.Items[name])
+	}
+	return nil
+}
+
+// GetDefinition returns the ScenarioDefinition of the scenario with the given ID
+func (m *Manager) GetDefinition(scenarioID string) (*v1alpha1.ScenarioDefinition, error) {
+	list, err := m.listDefinitions()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range list.Items {
+		if list.Items[i].Spec.ID == scenarioID {
+			return &list.Items[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("scenario with ID '%s' not found", scenarioID)
+}
+
+// DeleteDefinition deletes the ScenarioDefinition of the scenario with the given ID
+func (m *Manager) DeleteDefinition(scenarioID string) error {
+	definition, err := m.GetDefinition(scenarioID)
+	if err != nil {
+		return err
+	}
+
+	if err := m.client.ScenarioDefinitions().Delete(context.TODO(), definition.Name, metav1.DeleteOptions{}); err != nil {
+		return fmt.Errorf("failed to delete scenario definition %s: %w", definition.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package scenarios
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/DevOpsBeerer/dbeerer-cli/pkg/apis/devopsbeerer/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateDefinitionChartDir(t *testing.T) {
+	tests := []struct {
+		dir   string
+		valid bool
+	}{
+		{dir: "", valid: true},
+		{dir: "oidc", valid: true},
+		{dir: "charts/oidc", valid: true},
+		{dir: "../oidc", valid: false},
+		{dir: "charts/../oidc", valid: false},
+		{dir: "/charts/oidc", valid: false},
+	}
+
+	for _, tt := range tests {
+		definition := &v1alpha1.ScenarioDefinition{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: "ScenarioDefinition"},
+			ObjectMeta: metav1.ObjectMeta{Name: "oidc"},
+		}
+		definition.Spec.ID = "oidc"
+		definition.Spec.Name = "OIDC"
+		definition.Spec.HelmChart.Link = "https://github.com/DevOpsBeerer/playground"
+		definition.Spec.HelmChart.Dir = tt.dir
+
+		err := ValidateDefinition(definition)
+		if tt.valid && err != nil {
+			t.Errorf("ValidateDefinition(dir %q) error = %v", tt.dir, err)
+		}
+		if !tt.valid && (err == nil || !strings.Contains(err.Error(), "spec.helmChart.dir")) {
+			t.Errorf("ValidateDefinition(dir %q) error = %v, want spec.helmChart.dir error", tt.dir, err)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package scenarios
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/archive"
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/helm"
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/sources"
+	"github.com/DevOpsBeerer/dbeerer-cli/pkg/apis/devopsbeerer/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ModeOperator leaves the Helm install to the in-cluster operator
+	ModeOperator = "operator"
+	// ModeDirect installs the Helm chart from the CLI
+	ModeDirect = "direct"
+
+	// ModeAnnotation records on the ActiveScenario who installed the chart,
+	// so stop knows whether it has to remove the Helm release itself
+	ModeAnnotation = "devopsbeerer.ch/mode"
+)
+
+// Phases the CLI reports on ActiveScenarios it installs directly
+const (
+	PhaseInstalling = "Installing"
+	PhaseRunning    = "Running"
+	PhaseFailed     = "Failed"
+)
+
+// Modes lists the supported install modes
+var Modes = []string{ModeOperator, ModeDirect}
+
+// DownloadChart downloads the chart of a scenario into destPath
+// Scenarios without a chart link or ref use the configured chart source
+// and ref, the link's scheme selects the kind of source. When the scenario
+// pins a digest, the downloaded chart must match it
+func (m *Manager) DownloadChart(scenario *Scenario, destPath string) (*sources.Download, error) {
+	link := scenario.HelmChart.Link
+	if link == "" {
+		link = m.config.ChartSource
+	}
+
+	source, err := sources.ForLink(link, m.config)
+	if err != nil {
+		return nil, err
+	}
+
+	download, err := source.Fetch(scenario.ChartDir(), scenario.HelmChart.Ref, destPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyDigest(destPath, scenario.HelmChart.Digest); err != nil {
+		return nil, err
+	}
+
+	return download, nil
+}
+
+// verifyDigest compares the digest of a downloaded chart with the expected
+// one, an empty expectation skips the check
+func verifyDigest(chartPath, expected string) error {
+	if expected == "" {
+		return nil
+	}
+
+	actual, err := archive.DirDigest(chartPath, DefinitionFile)
+	if err != nil {
+		return fmt.Errorf("failed to compute chart digest: %w", err)
+	}
+	if actual != expected {
+		return fmt.Errorf("chart digest mismatch: expected %s, got %s", expected, actual)
+	}
+
+	fmt.Printf("🔒 Chart digest verified: %s\n", actual)
+	return nil
+}
+
+// installDirect downloads the scenario chart and installs it with Helm,
+// reporting progress on the ActiveScenario status
+func (m *Manager) installDirect(slot string, scenario *Scenario, values map[string]interface{}) error {
+	releaseName := HelmReleaseName(scenario.ID)
+	namespace := m.getHelmNamespace(scenario.ID)
+
+	if err := m.UpdateActiveScenarioStatus(slot, scenario.ID, PhaseInstalling, releaseName); err != nil {
+		return fmt.Errorf("failed to update active scenario status: %w", err)
+	}
+
+	if err := m.installRelease(slot, scenario, releaseName, namespace, values); err != nil {
+		if statusErr := m.UpdateActiveScenarioStatus(slot, scenario.ID, PhaseFailed, releaseName); statusErr != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Failed to update active scenario status: %v\n", statusErr)
+		}
+		return err
+	}
+
+	if err := m.UpdateActiveScenarioStatus(slot, scenario.ID, PhaseRunning, releaseName); err != nil {
+		return fmt.Errorf("failed to update active scenario status: %w", err)
+	}
+
+	fmt.Printf("✅ Scenario '%s' installed as release %s in namespace %s\n", scenario.Name, releaseName, namespace)
+	return nil
+}
+
+// installRelease downloads the chart to a temporary directory, records the
+// revision it came from and installs it
+func (m *Manager) installRelease(slot string, scenario *Scenario, releaseName, namespace string, values map[string]interface{}) error {
+	tempDir, err := os.MkdirTemp("", "dbeerer-chart-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	download, err := m.DownloadChart(scenario, tempDir)
+	if err != nil {
+		return err
+	}
+
+	if download.Revision != "" {
+		if err := m.recordChartCommit(slot, download.Revision); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Failed to record chart commit: %v\n", err)
+		}
+	}
+
+	return helm.NewManager(namespace, m.config).InstallScenario(releaseName, tempDir, values)
+}
+
+// recordChartCommit stores the commit the chart was downloaded from on the
+// ActiveScenario status
+func (m *Manager) recordChartCommit(slot, commit string) error {
+	current, err := m.client.ActiveScenarios().
+		Get(context.TODO(), activeName(slot), metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	current.Status.ChartCommit = commit
+	_, err = m.client.ActiveScenarios().
+		UpdateStatus(context.TODO(), current, metav1.UpdateOptions{})
+	return err
+}
+
+// uninstallDirect removes the Helm release of a directly installed scenario
+// A release that is already gone is not an error
+func (m *Manager) uninstallDirect(scenarioID string) error {
+	releaseName := HelmReleaseName(scenarioID)
+	helmManager := helm.NewManager(m.getHelmNamespace(scenarioID), m.config)
+
+	exists, _, err := helmManager.GetScenarioStatus(releaseName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return nil
+	}
+
+	return helmManager.UninstallScenario(releaseName)
+}
+
+// modeOf returns the install mode recorded on an ActiveScenario
+// ActiveScenarios created before modes existed were installed by the operator
+func modeOf(active *v1alpha1.ActiveScenario) string {
+	if mode := active.Annotations[ModeAnnotation]; mode != "" {
+		return mode
+	}
+	return ModeOperator
+}
+
+// isDirect reports whether the CLI installed the ActiveScenario's chart itself
+func isDirect(active *v1alpha1.ActiveScenario) bool {
+	return active.Annotations[ModeAnnotation] == ModeDirect
+}
@@ -0,0 +1,127 @@
+package scenarios
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SortFields lists the fields scenarios can be sorted by
+var SortFields = []string{"id", "name"}
+
+// Filter selects scenarios by tags, features and a free-text search
+// A scenario must carry every listed tag and feature to match
+type Filter struct {
+	Tags     []string
+	Features []string
+	Search   string
+}
+
+// Matches reports whether a scenario satisfies the filter
+func (f Filter) Matches(scenario Scenario) bool {
+	for _, tag := range f.Tags {
+		if !containsFold(scenario.Tags, tag) {
+			return false
+		}
+	}
+
+	for _, feature := range f.Features {
+		if !containsFold(scenario.Features, feature) {
+			return false
+		}
+	}
+
+	if f.Search != "" {
+		search := strings.ToLower(f.Search)
+		text := strings.ToLower(strings.Join([]string{scenario.ID, scenario.Name, scenario.Description}, " "))
+		if !strings.Contains(text, search) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// FilterScenarios returns the scenarios matching the filter
+func FilterScenarios(scenarios []Scenario, filter Filter) []Scenario {
+	matched := make([]Scenario, 0, len(scenarios))
+	for _, scenario := range scenarios {
+		if filter.Matches(scenario) {
+			matched = append(matched, scenario)
+		}
+	}
+	return matched
+}
+
+// SortScenarios sorts scenarios in place by the given field
+func SortScenarios(scenarios []Scenario, field string) error {
+	var less func(a, b Scenario) bool
+
+	switch field {
+	case "id":
+		less = func(a, b Scenario) bool { return naturalLess(a.ID, b.ID) }
+	case "name":
+		less = func(a, b Scenario) bool { return naturalLess(strings.ToLower(a.Name), strings.ToLower(b.Name)) }
+	default:
+		return fmt.Errorf("cannot sort by '%s' (use %s)", field, strings.Join(SortFields, " or "))
+	}
+
+	sort.SliceStable(scenarios, func(i, j int) bool {
+		return less(scenarios[i], scenarios[j])
+	})
+	return nil
+}
+
+// naturalLess compares strings with runs of digits compared by their
+// numeric value, so scenario-2 sorts before scenario-10
+func naturalLess(a, b string) bool {
+	for a != "" && b != "" {
+		if isDigit(a[0]) && isDigit(b[0]) {
+			numberA, restA := digitRun(a)
+			numberB, restB := digitRun(b)
+			// Leading zeros don't change the value, only break ties
+			valueA, valueB := strings.TrimLeft(numberA, "0"), strings.TrimLeft(numberB, "0")
+			if len(valueA) != len(valueB) {
+				return len(valueA) < len(valueB)
+			}
+			if valueA != valueB {
+				return valueA < valueB
+			}
+			if len(numberA) != len(numberB) {
+				return len(numberA) < len(numberB)
+			}
+			a, b = restA, restB
+			continue
+		}
+
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		a, b = a[1:], b[1:]
+	}
+	return len(a) < len(b)
+}
+
+// digitRun splits the leading digits off s
+func digitRun(s string) (string, string) {
+	end := 0
+	for end < len(s) && isDigit(s[end]) {
+		end++
+	}
+	return s[:end], s[end:]
+}
+
+// isDigit reports whether c is an ASCII digit
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// containsFold reports whether values contains target, ignoring case
+func containsFold(values []string, target string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package scenarios
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNaturalLess(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{a: "scenario-2", b: "scenario-10", want: true},
+		{a: "scenario-10", b: "scenario-2", want: false},
+		{a: "scenario-2", b: "scenario-2", want: false},
+		{a: "scenario-02", b: "scenario-2", want: false},
+		{a: "scenario-2", b: "scenario-02", want: true},
+		{a: "scenario-2a", b: "scenario-2b", want: true},
+		{a: "scenario", b: "scenario-1", want: true},
+		{a: "oidc-pkce", b: "saml", want: true},
+		{a: "v1.10.0", b: "v1.9.2", want: false},
+		{a: "scenario-99999999999999999999", b: "scenario-100000000000000000000", want: true},
+	}
+
+	for _, tt := range tests {
+		if got := naturalLess(tt.a, tt.b); got != tt.want {
+			t.Errorf("naturalLess(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSortScenarios(t *testing.T) {
+	var list []Scenario
+	for _, id := range []string{"scenario-10", "oidc", "scenario-2", "scenario-1"} {
+		list = append(list, Scenario{ID: id, Name: "Scenario " + id})
+	}
+
+	for _, field := range SortFields {
+		if err := SortScenarios(list, field); err != nil {
+			t.Fatalf("SortScenarios(%s) error = %v", field, err)
+		}
+
+		var ids []string
+		for _, scenario := range list {
+			ids = append(ids, scenario.ID)
+		}
+		if want := []string{"oidc", "scenario-1", "scenario-2", "scenario-10"}; !slices.Equal(ids, want) {
+			t.Errorf("SortScenarios(%s) = %v, want %v", field, ids, want)
+		}
+	}
+
+	if err := SortScenarios(list, "tags"); err == nil {
+		t.Error("SortScenarios(tags) accepted an unknown field")
+	}
+}
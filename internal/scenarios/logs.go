@@ -0,0 +1,121 @@
+package scenarios
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LogOptions selects the logs StreamLogs prints
+type LogOptions struct {
+	// Follow keeps streaming new log lines
+	Follow bool
+	// Tail limits the output to the last lines of each container, a
+	// negative value prints everything
+	Tail int64
+	// Container restricts the output to containers with this name
+	Container string
+}
+
+// StreamLogs writes the logs of every pod of the scenario in slot to out,
+// each line prefixed with its pod and container
+func (m *Manager) StreamLogs(slot string, opts LogOptions, out io.Writer) error {
+	active, err := m.getActive(slot)
+	if err != nil {
+		return err
+	}
+
+	namespace := m.getHelmNamespace(active.Spec.ScenarioID)
+	pods, err := m.kubeClient.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list pods in %s: %w", namespace, err)
+	}
+	if len(pods.Items) == 0 {
+		return fmt.Errorf("no pods found in namespace %s", namespace)
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		streams  int
+	)
+
+	for _, pod := range pods.Items {
+		for _, container := range pod.Spec.Containers {
+			if opts.Container != "" && container.Name != opts.Container {
+				continue
+			}
+
+			logOptions := &corev1.PodLogOptions{
+				Container: container.Name,
+				Follow:    opts.Follow,
+			}
+			if opts.Tail >= 0 {
+				logOptions.TailLines = &opts.Tail
+			}
+
+			prefix := fmt.Sprintf("[%s/%s] ", pod.Name, container.Name)
+			request := m.streamClient.CoreV1().Pods(namespace).GetLogs(pod.Name, logOptions)
+
+			streams++
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				err := copyLines(request.Stream, m.config.Timeouts.Request, prefix, out, &mu)
+				if err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = fmt.Errorf("failed to stream logs of %s/%s: %w", pod.Name, container.Name, err)
+					}
+					mu.Unlock()
+				}
+			}()
+		}
+	}
+
+	if streams == 0 {
+		return fmt.Errorf("no container named %s found in namespace %s", opts.Container, namespace)
+	}
+
+	wg.Wait()
+	return firstErr
+}
+
+// copyLines copies a log stream line by line, prefixing each line
+// mu serializes writes so lines of concurrent streams don't interleave
+// Only opening the stream is bounded by timeout, reading large or followed
+// logs takes as long as it takes
+func copyLines(open func(context.Context) (io.ReadCloser, error), timeout time.Duration, prefix string, out io.Writer, mu *sync.Mutex) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	timer := time.AfterFunc(timeout, cancel)
+	stream, err := open(ctx)
+	if !timer.Stop() {
+		if err == nil {
+			stream.Close()
+		}
+		return fmt.Errorf("timed out after %s opening the log stream", timeout)
+	}
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		mu.Lock()
+		fmt.Fprintf(out, "%s%s\n", prefix, scanner.Text())
+		mu.Unlock()
+	}
+	return scanner.Err()
+}
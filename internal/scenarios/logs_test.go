@@ -0,0 +1,53 @@
+package scenarios
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// slowReader returns its lines one at a time, pausing before each
+type slowReader struct {
+	lines []string
+	pause time.Duration
+}
+
+func (r *slowReader) Read(p []byte) (int, error) {
+	if len(r.lines) == 0 {
+		return 0, io.EOF
+	}
+	time.Sleep(r.pause)
+	n := copy(p, r.lines[0])
+	r.lines = r.lines[1:]
+	return n, nil
+}
+
+func TestCopyLinesOutlivesTimeout(t *testing.T) {
+	open := func(ctx context.Context) (io.ReadCloser, error) {
+		return io.NopCloser(&slowReader{lines: []string{"one\n", "two\n", "three\n"}, pause: 20 * time.Millisecond}), nil
+	}
+
+	var out bytes.Buffer
+	if err := copyLines(open, 30*time.Millisecond, "[pod/app] ", &out, &sync.Mutex{}); err != nil {
+		t.Fatalf("copyLines() error = %v", err)
+	}
+	if want := "[pod/app] one\n[pod/app] two\n[pod/app] three\n"; out.String() != want {
+		t.Errorf("copyLines() wrote %q, want %q", out.String(), want)
+	}
+}
+
+func TestCopyLinesOpenTimeout(t *testing.T) {
+	open := func(ctx context.Context) (io.ReadCloser, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	err := copyLines(open, 10*time.Millisecond, "", io.Discard, &sync.Mutex{})
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("copyLines() error = %v, want timeout", err)
+	}
+}
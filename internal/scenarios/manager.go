@@ -2,39 +2,42 @@ package scenarios
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"os"
 	"os/exec"
+	"strings"
 	"time"
 
-	"path/filepath"
-
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/config"
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/httpclient"
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/kube"
+	"github.com/DevOpsBeerer/dbeerer-cli/pkg/apis/devopsbeerer/v1alpha1"
 	"helm.sh/helm/v3/pkg/cli"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/client-go/dynamic"
-	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 )
 
 const (
-	MetadataURL    = "https://raw.githubusercontent.com/DevOpsBeerer/playground-scenarios-charts/refs/heads/main/metadata.json"
-	RequestTimeout = 10 * time.Second
-	ReleaseName    = "devopsbeerer-scenario"
-	ChartBaseURL   = "https://raw.githubusercontent.com/DevOpsBeerer/playground-scenarios-charts/refs/heads/main"
+	MetadataURL  = "https://raw.githubusercontent.com/DevOpsBeerer/playground-scenarios-charts/refs/heads/main/metadata.json"
+	ReleaseName  = "devopsbeerer-scenario"
+	ChartBaseURL = "https://raw.githubusercontent.com/DevOpsBeerer/playground-scenarios-charts/refs/heads/main"
 )
 
-// getHelmReleaseName returns the Helm release name for a scenario
+// HelmReleaseName returns the Helm release name for a scenario
 // Using scenario ID ensures unique release names and allows multiple scenarios
 // to be installed in different namespaces during development/testing
-func getHelmReleaseName(scenarioID string) string {
+func HelmReleaseName(scenarioID string) string {
 	return fmt.Sprintf("devopsbeerer-%s", scenarioID)
 }
 
 // getHelmNamespace returns the namespace for a scenario
 // Each scenario gets its own namespace for isolation
-func getHelmNamespace(scenarioID string) string {
-	return fmt.Sprintf("devopsbeerer-%s", scenarioID)
+func (m *Manager) getHelmNamespace(scenarioID string) string {
+	return m.config.NamespacePrefix + scenarioID
 }
 
 // Scenario represents a single scenario from metadata
@@ -47,16 +50,32 @@ type Scenario struct {
 	HelmChart   struct {
 		Link string `json:"link"`
 		Dir  string `json:"dir"`
+		// Ref pins the chart to a branch, tag or commit SHA
+		Ref string `json:"ref,omitempty"`
+		// Digest is the expected "sha256:<hex>" of the chart directory
+		Digest string `json:"digest,omitempty"`
 	} `json:"helmChart"`
 }
 
+// ChartDir returns the chart directory inside the chart repository
+// Scenarios without an explicit directory use their ID
+func (s Scenario) ChartDir() string {
+	if s.HelmChart.Dir != "" {
+		return s.HelmChart.Dir
+	}
+	return s.ID
+}
+
 // Manager handles scenario operations
 type Manager struct {
-	httpClient    *http.Client
-	settings      *cli.EnvSettings
-	namespace     string
-	dynamicClient dynamic.Interface
-	gvr           schema.GroupVersionResource
+	httpClient *http.Client
+	settings   *cli.EnvSettings
+	namespace  string
+	client     *v1alpha1.Client
+	kubeClient kubernetes.Interface
+	// streamClient has no request timeout, for long-lived log streams
+	streamClient kubernetes.Interface
+	config       *config.Config
 }
 
 // ActiveScenarioInfo contains information about the active scenario
@@ -68,53 +87,100 @@ type ActiveScenarioInfo struct {
 
 // ScenarioStatus represents the status of an active scenario
 type ScenarioStatus struct {
+	// Slot is the name of the ActiveScenario
+	Slot        string
+	Mode        string
 	ScenarioID  string
 	Phase       string
 	Message     string
 	HelmRelease string
 	StartTime   string
 	HelmStatus  string
+	// ChartCommit is the commit the chart was downloaded from, only known
+	// for scenarios installed in direct mode
+	ChartCommit string
+	// Values are the Helm value overrides the scenario was started with
+	Values map[string]interface{}
+}
+
+// ErrNoActiveScenario is returned when an operation needs a running scenario
+var ErrNoActiveScenario = errors.New("no active scenario found")
+
+// InstallOptions controls how a scenario is installed
+type InstallOptions struct {
+	// Mode is ModeOperator or ModeDirect, empty means ModeOperator
+	Mode string
+	// Slot names the ActiveScenario to create, empty means the default one
+	// Other slots need multi-scenario mode
+	Slot string
+	// Values override the chart defaults, they are stored on the
+	// ActiveScenario for the operator and passed to Helm in direct mode
+	Values map[string]interface{}
 }
 
 // NewManager creates a new scenario manager
-func NewManager() (*Manager, error) {
+func NewManager(cfg *config.Config) (*Manager, error) {
 	settings := cli.New()
+	settings.KubeConfig = cfg.Kubeconfig
 
-	// Build kubeconfig path
-	var kubeconfig string = filepath.Join("/etc/rancher/k3s", "k3s.yaml")
+	restConfig, err := kube.RESTConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
 
-	// Use the current context in kubeconfig
-	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	// Create typed client for the DevOpsBeerer resources
+	client, err := v1alpha1.NewForConfig(restConfig)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build kubeconfig: %w", err)
+		return nil, err
 	}
 
-	// Create dynamic client
-	dynamicClient, err := dynamic.NewForConfig(config)
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+		return nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
 	}
 
-	// Define GVR for ScenarioDefinition
-	gvr := schema.GroupVersionResource{
-		Group:    "devopsbeerer.ch",
-		Version:  "v1alpha1",
-		Resource: "scenariodefinitions",
+	// Following logs must outlive the request timeout, callers bound
+	// streams with a context instead
+	streamConfig := rest.CopyConfig(restConfig)
+	streamConfig.Timeout = 0
+	streamClient, err := kubernetes.NewForConfig(streamConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+
+	httpClient, err := httpclient.New(cfg, cfg.Timeouts.Request)
+	if err != nil {
+		return nil, err
 	}
 
 	return &Manager{
-		dynamicClient: dynamicClient,
-		gvr:           gvr,
-		settings:      settings,
-		httpClient: &http.Client{
-			Timeout: RequestTimeout,
-		},
+		client:       client,
+		kubeClient:   kubeClient,
+		streamClient: streamClient,
+		settings:     settings,
+		config:       cfg,
+		httpClient:   httpClient,
 	}, nil
 
 }
 
 // InstallScenario installs a scenario using Helm
-func (m *Manager) InstallScenario(scenarioID string) error {
+// In operator mode the in-cluster operator installs the chart once the
+// ActiveScenario exists, in direct mode the CLI installs it
+func (m *Manager) InstallScenario(scenarioID string, opts InstallOptions) error {
+	mode := opts.Mode
+	if mode == "" {
+		mode = ModeOperator
+	}
+	if mode != ModeOperator && mode != ModeDirect {
+		return fmt.Errorf("unknown mode '%s', expected one of: %s", mode, strings.Join(Modes, ", "))
+	}
+
+	if err := m.checkSlot(opts.Slot); err != nil {
+		return err
+	}
+	name := activeName(opts.Slot)
+
 	fmt.Printf("🔍 Checking if scenario exists: %s\n", scenarioID)
 
 	// First, verify the scenario exists
@@ -125,143 +191,157 @@ func (m *Manager) InstallScenario(scenarioID string) error {
 
 	fmt.Printf("✅ Found scenario: %s\n", scenario.Name)
 
-	activeGVR := schema.GroupVersionResource{
-		Group:    "devopsbeerer.ch",
-		Version:  "v1alpha1",
-		Resource: "activescenarios",
+	values, err := encodeValues(opts.Values)
+	if err != nil {
+		return err
+	}
+
+	// Namespace and release are derived from the scenario ID, so a scenario
+	// can only run in one slot at a time
+	if err := m.checkNotRunningElsewhere(scenarioID, name); err != nil {
+		return err
 	}
 
 	// Try to get existing active scenario
-	_ = m.dynamicClient.Resource(activeGVR).Delete(context.TODO(), "current-playground-scenario", metav1.DeleteOptions{})
+	// A directly installed one has no operator to remove its release
+	if previous, err := m.client.ActiveScenarios().Get(context.TODO(), name, metav1.GetOptions{}); err == nil && isDirect(previous) {
+		if err := m.uninstallDirect(previous.Spec.ScenarioID); err != nil {
+			return fmt.Errorf("failed to remove previous scenario: %w", err)
+		}
+	}
+	_ = m.client.ActiveScenarios().Delete(context.TODO(), name, metav1.DeleteOptions{})
 
 	// Create the ActiveScenario CRD first
 	fmt.Printf("📝 Creating ActiveScenario resource...\n")
 
-	activeScenario := &unstructured.Unstructured{
-		Object: map[string]any{
-			"apiVersion": "devopsbeerer.ch/v1alpha1",
-			"kind":       "ActiveScenario",
-			"metadata": map[string]any{
-				"name": "current-playground-scenario",
-			},
-			"spec": map[string]any{
-				"scenarioId": scenarioID,
+	activeScenario := &v1alpha1.ActiveScenario{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Annotations: map[string]string{
+				ModeAnnotation: mode,
 			},
 		},
+		Spec: v1alpha1.ActiveScenarioSpec{
+			ScenarioID: scenarioID,
+			Values:     values,
+		},
 	}
 
 	// Create the ActiveScenario
-	_, err = m.dynamicClient.Resource(activeGVR).
-		Create(context.TODO(), activeScenario, metav1.CreateOptions{})
+	_, err = m.client.ActiveScenarios().Create(context.TODO(), activeScenario, metav1.CreateOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to create active scenario: %w", err)
 	}
 
+	if mode == ModeDirect {
+		return m.installDirect(name, scenario, opts.Values)
+	}
+
 	fmt.Printf("🔁 Scenario '%s' is getting installed\n", scenario.Name)
 
 	return nil
 }
 
-// UninstallScenario removes the current scenario deployment
-func (m *Manager) UninstallScenario() error {
-	activeGVR := schema.GroupVersionResource{
-		Group:    "devopsbeerer.ch",
-		Version:  "v1alpha1",
-		Resource: "activescenarios",
-	}
-
-	err := m.dynamicClient.Resource(activeGVR).Delete(context.TODO(), "current-playground-scenario", metav1.DeleteOptions{})
-
+// UninstallScenario removes the scenario running in slot and waits until
+// its ActiveScenario, Helm release and namespace are gone
+// It returns ErrNoActiveScenario when nothing is running
+func (m *Manager) UninstallScenario(slot string) error {
+	active, err := m.getActive(slot)
 	if err != nil {
-		fmt.Printf("No active scenario found")
 		return err
 	}
 
-	fmt.Printf("✅ Active scenario is getting deleted")
+	name := active.Name
+	scenarioID := active.Spec.ScenarioID
+	namespace := m.getHelmNamespace(scenarioID)
 
-	return nil
-}
+	// Nothing else removes the release of a directly installed scenario
+	if isDirect(active) {
+		if err := m.uninstallDirect(scenarioID); err != nil {
+			return err
+		}
+		// Helm leaves the namespace it created behind
+		if err := m.deleteNamespace(namespace); err != nil {
+			return err
+		}
+	}
 
-// updateActiveScenarioStatus updates the status of the ActiveScenario
-func (m *Manager) UpdateActiveScenarioStatus(scenarioID string, phase string, helmRelease string) error {
-	// Define GVR for ActiveScenario
-	activeGVR := schema.GroupVersionResource{
-		Group:    "devopsbeerer.ch",
-		Version:  "v1alpha1",
-		Resource: "activescenarios",
+	err = m.client.ActiveScenarios().Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete active scenario: %w", err)
 	}
 
-	// Get the singleton active scenario
-	current, err := m.dynamicClient.Resource(activeGVR).
-		Get(context.TODO(), "current-playground-scenario", metav1.GetOptions{})
+	fmt.Printf("🗑️  Active scenario '%s' is getting deleted\n", scenarioID)
+
+	return m.waitTeardown(name, scenarioID)
+}
+
+// UpdateActiveScenarioStatus updates the status of the ActiveScenario in slot
+func (m *Manager) UpdateActiveScenarioStatus(slot, scenarioID, phase, helmRelease string) error {
+	current, err := m.client.ActiveScenarios().
+		Get(context.TODO(), activeName(slot), metav1.GetOptions{})
 	if err != nil {
 		return err
 	}
 
 	// Update status
-	status := map[string]interface{}{
-		"phase":              phase,
-		"helmReleaseName":    helmRelease,
-		"startTime":          time.Now().Format(time.RFC3339),
-		"lastTransitionTime": time.Now().Format(time.RFC3339),
+	now := time.Now().Format(time.RFC3339)
+	current.Status.HelmReleaseName = helmRelease
+	if current.Status.Phase != phase {
+		current.Status.LastTransitionTime = now
+	}
+	if current.Status.StartTime == "" {
+		current.Status.StartTime = now
 	}
+	current.Status.Phase = phase
 
 	// Get scenario name
 	if scenario, err := m.GetScenario(scenarioID); err == nil {
-		status["scenarioName"] = scenario.Name
-	}
-
-	if err := unstructured.SetNestedMap(current.Object, status, "status"); err != nil {
-		return err
+		current.Status.ScenarioName = scenario.Name
 	}
 
 	// Update the resource
-	_, err = m.dynamicClient.Resource(activeGVR).
+	_, err = m.client.ActiveScenarios().
 		UpdateStatus(context.TODO(), current, metav1.UpdateOptions{})
 	return err
 }
 
-// GetScenarioStatus checks if a scenario is currently deployed
-func (m *Manager) GetScenarioStatus() (*ScenarioStatus, error) {
-	activeGVR := schema.GroupVersionResource{
-		Group:    "devopsbeerer.ch",
-		Version:  "v1alpha1",
-		Resource: "activescenarios",
-	}
-
-	obj, err := m.dynamicClient.Resource(activeGVR).
-		Get(context.TODO(), "current-playground-scenario", metav1.GetOptions{})
+// GetScenarioStatus checks if a scenario is currently deployed in slot
+func (m *Manager) GetScenarioStatus(slot string) (*ScenarioStatus, error) {
+	active, err := m.getActive(slot)
 	if err != nil {
-		return nil, fmt.Errorf("no active scenario found")
+		return nil, err
 	}
 
-	status := &ScenarioStatus{}
-
-	// Extract spec info
-	if scenarioID, found, _ := unstructured.NestedString(obj.Object, "spec", "scenarioId"); found {
-		status.ScenarioID = scenarioID
-	}
+	return m.statusFromActive(active)
+}
 
-	// Extract status info
-	if phase, found, _ := unstructured.NestedString(obj.Object, "status", "phase"); found {
-		status.Phase = phase
-	}
-	if message, found, _ := unstructured.NestedString(obj.Object, "status", "message"); found {
-		status.Message = message
-	}
-	if helmRelease, found, _ := unstructured.NestedString(obj.Object, "status", "helmReleaseName"); found {
-		status.HelmRelease = helmRelease
-	}
-	if startTime, found, _ := unstructured.NestedString(obj.Object, "status", "startTime"); found {
-		status.StartTime = startTime
+// statusFromActive builds the status of an ActiveScenario, including the
+// state of its Helm release
+func (m *Manager) statusFromActive(active *v1alpha1.ActiveScenario) (*ScenarioStatus, error) {
+	status := &ScenarioStatus{
+		Slot:        active.Name,
+		Mode:        modeOf(active),
+		ScenarioID:  active.Spec.ScenarioID,
+		Phase:       active.Status.Phase,
+		Message:     active.Status.Message,
+		HelmRelease: active.Status.HelmReleaseName,
+		StartTime:   active.Status.StartTime,
+		ChartCommit: active.Status.ChartCommit,
+	}
+
+	values, err := decodeValues(active.Spec.Values)
+	if err != nil {
+		return nil, err
 	}
+	status.Values = values
 
 	// Also check Helm status
 	if status.ScenarioID != "" {
-		helmReleaseName := getHelmReleaseName(status.ScenarioID)
-		helmNamespace := getHelmNamespace(status.ScenarioID)
+		helmReleaseName := HelmReleaseName(status.ScenarioID)
+		helmNamespace := m.getHelmNamespace(status.ScenarioID)
 
-		cmd := exec.Command("helm", "status", helmReleaseName, "-n", helmNamespace, "-o", "json")
+		cmd := exec.Command("helm", "status", helmReleaseName, "-n", helmNamespace, "-o", "json", "--kubeconfig", m.config.Kubeconfig)
 		if _, err := cmd.Output(); err == nil {
 			// Parse helm status if needed
 			status.HelmStatus = "deployed"
@@ -274,70 +354,41 @@ func (m *Manager) GetScenarioStatus() (*ScenarioStatus, error) {
 // ListScenarios fetches and returns all available scenarios from Kubernetes
 func (m *Manager) ListScenarios() ([]Scenario, error) {
 	// List all ScenarioDefinitions (cluster-scoped)
-	list, err := m.dynamicClient.Resource(m.gvr).
-		List(context.TODO(), metav1.ListOptions{})
+	list, err := m.listDefinitions()
 	if err != nil {
-		return nil, fmt.Errorf("failed to list scenario definitions: %w", err)
+		return nil, err
 	}
 
 	scenarios := make([]Scenario, 0, len(list.Items))
 
 	for _, item := range list.Items {
-		scenario, err := m.unstructuredToScenario(&item)
-		if err != nil {
+		if item.Spec.ID == "" {
 			// Log error but continue with other scenarios
-			fmt.Printf("Warning: failed to parse scenario %s: %v\n",
-				item.GetName(), err)
+			fmt.Fprintf(os.Stderr, "Warning: failed to parse scenario %s: spec.id not set\n",
+				item.GetName())
 			continue
 		}
-		scenarios = append(scenarios, scenario)
+		scenarios = append(scenarios, scenarioFromDefinition(&item))
 	}
 
 	return scenarios, nil
 }
 
-// unstructuredToScenario converts an unstructured object to Scenario
-func (m *Manager) unstructuredToScenario(obj *unstructured.Unstructured) (Scenario, error) {
+// scenarioFromDefinition converts a ScenarioDefinition to Scenario
+func scenarioFromDefinition(definition *v1alpha1.ScenarioDefinition) Scenario {
 	var scenario Scenario
 
-	// Extract spec
-	spec, found, err := unstructured.NestedMap(obj.Object, "spec")
-	if err != nil || !found {
-		return scenario, fmt.Errorf("spec not found")
-	}
-
-	// Extract fields from spec
-	if name, ok := spec["name"].(string); ok {
-		scenario.Name = name
-	}
-	if id, ok := spec["id"].(string); ok {
-		scenario.ID = id
-	}
-	if desc, ok := spec["description"].(string); ok {
-		scenario.Description = desc
-	}
-
-	// Extract tags
-	if tags, found, err := unstructured.NestedStringSlice(obj.Object, "spec", "tags"); err == nil && found {
-		scenario.Tags = tags
-	}
-
-	// Extract features
-	if features, found, err := unstructured.NestedStringSlice(obj.Object, "spec", "features"); err == nil && found {
-		scenario.Features = features
-	}
-
-	// Extract helmChart
-	if helmChart, found, err := unstructured.NestedMap(obj.Object, "spec", "helmChart"); err == nil && found {
-		if link, ok := helmChart["link"].(string); ok {
-			scenario.HelmChart.Link = link
-		}
-		if dir, ok := helmChart["dir"].(string); ok {
-			scenario.HelmChart.Dir = dir
-		}
-	}
-
-	return scenario, nil
+	scenario.Name = definition.Spec.Name
+	scenario.ID = definition.Spec.ID
+	scenario.Description = definition.Spec.Description
+	scenario.Tags = definition.Spec.Tags
+	scenario.Features = definition.Spec.Features
+	scenario.HelmChart.Link = definition.Spec.HelmChart.Link
+	scenario.HelmChart.Dir = definition.Spec.HelmChart.Dir
+	scenario.HelmChart.Ref = definition.Spec.HelmChart.Ref
+	scenario.HelmChart.Digest = definition.Spec.HelmChart.Digest
+
+	return scenario
 }
 
 // GetScenario fetches a specific scenario by ID
@@ -373,36 +424,18 @@ func (m *Manager) FindScenario(id string) (*Scenario, error) {
 	return nil, fmt.Errorf("scenario '%s' not found", id)
 }
 
-func (m *Manager) GetActiveScenario() (*ActiveScenarioInfo, error) {
-	// Define GVR for ActiveScenario
-	activeGVR := schema.GroupVersionResource{
-		Group:    "devopsbeerer.ch",
-		Version:  "v1alpha1",
-		Resource: "activescenarios",
-	}
-
-	// Get the singleton active scenario
-	obj, err := m.dynamicClient.Resource(activeGVR).
-		Get(context.TODO(), "current-playground-scenario", metav1.GetOptions{})
+// GetActiveScenario returns the scenario running in slot
+func (m *Manager) GetActiveScenario(slot string) (*ActiveScenarioInfo, error) {
+	active, err := m.client.ActiveScenarios().
+		Get(context.TODO(), activeName(slot), metav1.GetOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("no active scenario found: %w", err)
 	}
 
-	info := &ActiveScenarioInfo{}
-
-	// Extract scenario ID
-	if scenarioID, found, err := unstructured.NestedString(obj.Object, "spec", "scenarioId"); err == nil && found {
-		info.ScenarioID = scenarioID
-	}
-
-	// Extract status phase
-	if phase, found, err := unstructured.NestedString(obj.Object, "status", "phase"); err == nil && found {
-		info.Phase = phase
-	}
-
-	// Extract scenario name from status
-	if name, found, err := unstructured.NestedString(obj.Object, "status", "scenarioName"); err == nil && found {
-		info.ScenarioName = name
+	info := &ActiveScenarioInfo{
+		ScenarioID:   active.Spec.ScenarioID,
+		Phase:        active.Status.Phase,
+		ScenarioName: active.Status.ScenarioName,
 	}
 
 	return info, nil
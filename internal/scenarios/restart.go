@@ -0,0 +1,73 @@
+package scenarios
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// RestartedAtAnnotation is the pod template annotation 'kubectl rollout
+// restart' sets, changing it rolls the Deployment
+const RestartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+// RestartScenario rolls every Deployment of the scenario in slot, like
+// 'kubectl rollout restart', keeping its data and configuration
+func (m *Manager) RestartScenario(slot string) error {
+	active, err := m.getActive(slot)
+	if err != nil {
+		return err
+	}
+
+	namespace := m.getHelmNamespace(active.Spec.ScenarioID)
+	deployments, err := m.kubeClient.AppsV1().Deployments(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list deployments in %s: %w", namespace, err)
+	}
+	if len(deployments.Items) == 0 {
+		return fmt.Errorf("no deployments found in namespace %s", namespace)
+	}
+
+	patch := fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{%q:%q}}}}}`,
+		RestartedAtAnnotation, time.Now().Format(time.RFC3339))
+
+	for _, deployment := range deployments.Items {
+		_, err := m.kubeClient.AppsV1().Deployments(namespace).
+			Patch(context.TODO(), deployment.Name, types.StrategicMergePatchType, []byte(patch), metav1.PatchOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to restart deployment %s: %w", deployment.Name, err)
+		}
+		fmt.Printf("🔁 Restarted deployment %s\n", deployment.Name)
+	}
+
+	return nil
+}
+
+// ResetScenario deletes the scenario in slot, waits for its teardown to
+// finish and starts it again with the same mode and values
+func (m *Manager) ResetScenario(slot string) error {
+	active, err := m.getActive(slot)
+	if err != nil {
+		return err
+	}
+
+	scenarioID := active.Spec.ScenarioID
+	values, err := decodeValues(active.Spec.Values)
+	if err != nil {
+		return err
+	}
+	opts := InstallOptions{
+		Mode:   active.Annotations[ModeAnnotation],
+		Slot:   slot,
+		Values: values,
+	}
+
+	fmt.Printf("🗑️  Removing scenario '%s'...\n", scenarioID)
+	if err := m.UninstallScenario(slot); err != nil {
+		return err
+	}
+
+	return m.InstallScenario(scenarioID, opts)
+}
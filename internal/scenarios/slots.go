@@ -0,0 +1,101 @@
+package scenarios
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/DevOpsBeerer/dbeerer-cli/pkg/apis/devopsbeerer/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// activeName returns the ActiveScenario name of a slot
+// The empty slot is the default singleton the operator has always watched
+func activeName(slot string) string {
+	if slot == "" {
+		return v1alpha1.DefaultActiveScenarioName
+	}
+	return slot
+}
+
+// checkSlot verifies a slot can be used with the current configuration
+func (m *Manager) checkSlot(slot string) error {
+	if activeName(slot) == v1alpha1.DefaultActiveScenarioName {
+		return nil
+	}
+
+	if !m.config.MultiScenario {
+		return fmt.Errorf("running scenarios side by side needs multi-scenario mode, enable it with: dbeerer config set multiScenario true")
+	}
+	if errs := validation.IsDNS1123Subdomain(slot); len(errs) > 0 {
+		return fmt.Errorf("invalid slot name '%s': %s", slot, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// checkNotRunningElsewhere fails when scenarioID runs in a slot other than name
+func (m *Manager) checkNotRunningElsewhere(scenarioID, name string) error {
+	list, err := m.listActive()
+	if err != nil {
+		return err
+	}
+
+	for _, active := range list.Items {
+		if active.Name != name && active.Spec.ScenarioID == scenarioID {
+			return fmt.Errorf("scenario '%s' is already running in slot '%s'", scenarioID, active.Name)
+		}
+	}
+	return nil
+}
+
+// listActive lists the ActiveScenarios, warning about and skipping the ones
+// that can't be decoded
+func (m *Manager) listActive() (*v1alpha1.ActiveScenarioList, error) {
+	list, err := m.client.ActiveScenarios().List(context.TODO(), metav1.ListOptions{})
+	if err = warnDecodeErrors(err); err != nil {
+		return nil, fmt.Errorf("failed to list active scenarios: %w", err)
+	}
+	return list, nil
+}
+
+// getActive returns the ActiveScenario of a slot
+// It returns ErrNoActiveScenario when the slot is empty
+func (m *Manager) getActive(slot string) (*v1alpha1.ActiveScenario, error) {
+	active, err := m.client.ActiveScenarios().Get(context.TODO(), activeName(slot), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, ErrNoActiveScenario
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active scenario: %w", err)
+	}
+	return active, nil
+}
+
+// ListActiveScenarios returns the status of every slot, the default slot first
+func (m *Manager) ListActiveScenarios() ([]ScenarioStatus, error) {
+	list, err := m.listActive()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]ScenarioStatus, 0, len(list.Items))
+	for i := range list.Items {
+		status, err := m.statusFromActive(&list.Items[i])
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, *status)
+	}
+
+	sort.SliceStable(statuses, func(i, j int) bool {
+		if (statuses[i].Slot == v1alpha1.DefaultActiveScenarioName) != (statuses[j].Slot == v1alpha1.DefaultActiveScenarioName) {
+			return statuses[i].Slot == v1alpha1.DefaultActiveScenarioName
+		}
+		return statuses[i].Slot < statuses[j].Slot
+	})
+
+	return statuses, nil
+}
@@ -0,0 +1,116 @@
+package scenarios
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/helm"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// teardownPollInterval is how often teardown progress is checked
+	teardownPollInterval = 2 * time.Second
+	// teardownReportInterval is how often a still running teardown is reported
+	teardownReportInterval = 10 * time.Second
+)
+
+// deleteNamespace deletes a namespace, a missing one is not an error
+func (m *Manager) deleteNamespace(namespace string) error {
+	err := m.kubeClient.CoreV1().Namespaces().Delete(context.TODO(), namespace, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete namespace %s: %w", namespace, err)
+	}
+	return nil
+}
+
+// waitTeardown polls until the ActiveScenario, the Helm release and the
+// namespace of the scenario are all gone, or the uninstall timeout hits
+func (m *Manager) waitTeardown(name, scenarioID string) error {
+	releaseName := HelmReleaseName(scenarioID)
+	namespace := m.getHelmNamespace(scenarioID)
+	helmManager := helm.NewManager(namespace, m.config)
+
+	checks := []struct {
+		name string
+		gone func() (bool, error)
+		done bool
+	}{
+		{name: "ActiveScenario " + name, gone: func() (bool, error) {
+			return m.activeScenarioGone(name)
+		}},
+		{name: "Helm release " + releaseName, gone: func() (bool, error) {
+			exists, _, err := helmManager.GetScenarioStatus(releaseName)
+			return !exists, err
+		}},
+		{name: "Namespace " + namespace, gone: func() (bool, error) {
+			return m.namespaceGone(namespace)
+		}},
+	}
+
+	timeout := m.config.Timeouts.Uninstall
+	start := time.Now()
+	lastReport := start
+
+	for {
+		var pending []string
+		for i := range checks {
+			if checks[i].done {
+				continue
+			}
+
+			gone, err := checks[i].gone()
+			if err != nil {
+				return err
+			}
+			if gone {
+				checks[i].done = true
+				fmt.Printf("✅ %s removed\n", checks[i].name)
+				continue
+			}
+			pending = append(pending, checks[i].name)
+		}
+
+		if len(pending) == 0 {
+			return nil
+		}
+
+		elapsed := time.Since(start)
+		if elapsed > timeout {
+			return fmt.Errorf("timed out after %s waiting for %s to be removed", timeout, strings.Join(pending, ", "))
+		}
+		if time.Since(lastReport) >= teardownReportInterval {
+			fmt.Printf("⏳ Still waiting for %s (%s elapsed)\n", strings.Join(pending, ", "), elapsed.Round(time.Second))
+			lastReport = time.Now()
+		}
+
+		time.Sleep(teardownPollInterval)
+	}
+}
+
+// activeScenarioGone reports whether the named ActiveScenario no longer exists
+func (m *Manager) activeScenarioGone(name string) (bool, error) {
+	_, err := m.client.ActiveScenarios().Get(context.TODO(), name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get active scenario: %w", err)
+	}
+	return false, nil
+}
+
+// namespaceGone reports whether the namespace no longer exists
+func (m *Manager) namespaceGone(namespace string) (bool, error) {
+	_, err := m.kubeClient.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get namespace %s: %w", namespace, err)
+	}
+	return false, nil
+}
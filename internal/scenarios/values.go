@@ -0,0 +1,35 @@
+package scenarios
+
+import (
+	"encoding/json"
+	"fmt"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// encodeValues converts Helm values into the ActiveScenario spec field
+// No values leave the field unset
+func encodeValues(values map[string]interface{}) (*apiextensionsv1.JSON, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	raw, err := json.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode values: %w", err)
+	}
+	return &apiextensionsv1.JSON{Raw: raw}, nil
+}
+
+// decodeValues converts the ActiveScenario spec field back into Helm values
+func decodeValues(values *apiextensionsv1.JSON) (map[string]interface{}, error) {
+	if values == nil || len(values.Raw) == 0 {
+		return nil, nil
+	}
+
+	decoded := map[string]interface{}{}
+	if err := json.Unmarshal(values.Raw, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode values: %w", err)
+	}
+	return decoded, nil
+}
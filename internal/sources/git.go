@@ -0,0 +1,255 @@
+package sources
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/cache"
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/config"
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/httpclient"
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/progress"
+)
+
+// gitSource fetches charts from any Git repository with the git command,
+// e.g. git+https://git.example.com/catalogue.git or ssh://git@host/catalogue
+// Checkouts are stored in the chart cache as tarballs
+type gitSource struct {
+	url        string
+	repo       string
+	defaultRef string
+	// env configures git's proxy, CA bundle and user agent
+	env      []string
+	cache    *cache.Cache
+	progress progress.Reporter
+}
+
+// newGitSource returns the source of a Git URL, "git+" prefixes are dropped
+func newGitSource(link string, cfg *config.Config) (*gitSource, error) {
+	gitURL := strings.TrimPrefix(link, "git+")
+
+	repo := gitURL
+	if u, err := url.Parse(gitURL); err == nil {
+		repo = repoKey(u)
+	}
+
+	return &gitSource{
+		url:        gitURL,
+		repo:       strings.TrimSuffix(repo, ".git"),
+		defaultRef: cfg.ChartRef,
+		env:        httpclient.GitEnv(cfg),
+		cache:      cache.New(),
+		progress:   progress.New(cfg),
+	}, nil
+}
+
+// List returns the directories of the repository holding a Chart.yaml
+func (s *gitSource) List() ([]string, error) {
+	file, err := s.fetch(s.defaultRef)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return chartDirs(file)
+}
+
+// Resolve returns the commit SHA ref points to, asking the remote
+func (s *gitSource) Resolve(dir, ref string) (string, error) {
+	if ref == "" {
+		ref = s.defaultRef
+	}
+	if cache.IsCommitSHA(ref) {
+		return ref, nil
+	}
+
+	output, err := s.git("", "ls-remote", s.url, ref)
+	if err != nil {
+		return "", err
+	}
+
+	sha, _, _ := strings.Cut(output, "\t")
+	if !cache.IsCommitSHA(sha) {
+		return "", fmt.Errorf("ref '%s' not found in %s", ref, s.repo)
+	}
+	return sha, nil
+}
+
+// Fetch checks out ref and extracts the chart directory
+func (s *gitSource) Fetch(dir, ref, dest string) (*Download, error) {
+	if ref == "" {
+		ref = s.defaultRef
+	}
+	fmt.Printf("📥 Fetching chart: %s (%s@%s)\n", dir, s.repo, ref)
+
+	file, err := s.fetch(ref)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	// Checkouts record their commit like 'git archive' does
+	revision, err := extractChart(file, dir, dest, s.progress)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("✅ Chart fetched successfully to %s (commit %s)\n", dest, revision)
+	return &Download{Repo: s.repo, Ref: ref, Revision: revision}, nil
+}
+
+// fetch returns the cached tarball of ref, checking it out first unless
+// ref is a commit that is already cached. When the remote can't be
+// reached a cached checkout is used as is
+func (s *gitSource) fetch(ref string) (*os.File, error) {
+	entry, err := s.cache.Lookup(s.repo, ref)
+	if err != nil {
+		return nil, err
+	}
+	if entry != nil && cache.IsCommitSHA(ref) {
+		fmt.Printf("📦 Using cached %s@%s\n", s.repo, ref)
+		return s.cache.OpenBlob(entry)
+	}
+
+	file, err := s.checkout(ref)
+	if err != nil && entry != nil {
+		fmt.Printf("⚠️  Fetch failed, using cached %s@%s: %v\n", s.repo, ref, err)
+		return s.cache.OpenBlob(entry)
+	}
+	return file, err
+}
+
+// checkout fetches ref shallowly and stores the tree in the chart cache
+func (s *gitSource) checkout(ref string) (*os.File, error) {
+	workDir, err := os.MkdirTemp("", "dbeerer-git-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	commands := [][]string{
+		{"init", "-q"},
+		{"fetch", "-q", "--depth", "1", s.url, ref},
+		{"-c", "advice.detachedHead=false", "checkout", "-q", "FETCH_HEAD"},
+	}
+	for _, args := range commands {
+		if _, err := s.git(workDir, args...); err != nil {
+			return nil, err
+		}
+	}
+
+	revision, err := s.git(workDir, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	revision = strings.TrimSpace(revision)
+
+	tarball := filepath.Join(workDir, ".git", "checkout.tar.gz")
+	if err := writeTree(workDir, tarball, revision); err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(tarball)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	entry, err := s.cache.Store(s.repo, ref, "", file)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.cache.OpenBlob(entry)
+}
+
+// writeTree packs a checkout, without .git, into a tarball laid out like
+// the archives of Git hosts: one top-level directory and the commit in
+// the global header
+func writeTree(dir, output, revision string) error {
+	file, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	err = tarWriter.WriteHeader(&tar.Header{
+		Typeflag:   tar.TypeXGlobalHeader,
+		Name:       "pax_global_header",
+		PAXRecords: map[string]string{"comment": revision},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() && entry.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		header := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     "checkout/" + filepath.ToSlash(rel),
+			Size:     int64(len(data)),
+			Mode:     0644,
+		}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err = tarWriter.Write(data)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to pack checkout: %w", err)
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return err
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// git runs git in dir and returns its standard output
+func (s *gitSource) git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	// Never hang on a credential prompt
+	cmd.Env = append(append(os.Environ(), s.env...), "GIT_TERMINAL_PROMPT=0")
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,53 @@
+package sources
+
+import (
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/config"
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/github"
+)
+
+// gitHubSource fetches charts from a GitHub repository's archives
+type gitHubSource struct {
+	repoURL string
+	config  *config.Config
+}
+
+// newGitHubSource returns the source of a GitHub repository URL
+func newGitHubSource(repoURL string, cfg *config.Config) (*gitHubSource, error) {
+	if _, _, err := github.ParseRepoURL(repoURL); err != nil {
+		return nil, err
+	}
+	return &gitHubSource{repoURL: repoURL, config: cfg}, nil
+}
+
+// List returns the scenario directories of the repository
+func (s *gitHubSource) List() ([]string, error) {
+	downloader, err := github.NewRepoDownloader(s.repoURL, "", s.config)
+	if err != nil {
+		return nil, err
+	}
+	return downloader.ListScenarios()
+}
+
+// Resolve returns the commit SHA ref points to
+func (s *gitHubSource) Resolve(dir, ref string) (string, error) {
+	downloader, err := github.NewRepoDownloader(s.repoURL, ref, s.config)
+	if err != nil {
+		return "", err
+	}
+	return downloader.ResolveRef()
+}
+
+// Fetch extracts the chart directory at ref from the repository archive
+func (s *gitHubSource) Fetch(dir, ref, dest string) (*Download, error) {
+	downloader, err := github.NewRepoDownloader(s.repoURL, ref, s.config)
+	if err != nil {
+		return nil, err
+	}
+
+	download, err := downloader.DownloadChart(dir, dest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Download{Repo: download.Repo, Ref: download.Ref, Revision: download.Commit}, nil
+}
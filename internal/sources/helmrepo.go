@@ -0,0 +1,211 @@
+package sources
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/archive"
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/cache"
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/config"
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/progress"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+// helmRepoSource fetches packaged charts from a Helm repository
+// The chart directory is the chart name and the ref a version or semver
+// constraint, empty meaning the latest version
+type helmRepoSource struct {
+	url      string
+	repo     string
+	client   *http.Client
+	cache    *cache.Cache
+	progress progress.Reporter
+}
+
+// newHelmRepoSource returns the source of a Helm repository base URL
+func newHelmRepoSource(u *url.URL, cfg *config.Config) (*helmRepoSource, error) {
+	client, err := newHTTPClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &helmRepoSource{
+		url:      strings.TrimSuffix(u.String(), "/"),
+		repo:     repoKey(u),
+		client:   client,
+		cache:    cache.New(),
+		progress: progress.New(cfg),
+	}, nil
+}
+
+// List returns the names of the charts in the repository
+func (s *helmRepoSource) List() ([]string, error) {
+	index, err := s.index()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(index.Entries))
+	for name := range index.Entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Resolve returns the chart version matching ref
+func (s *helmRepoSource) Resolve(dir, ref string) (string, error) {
+	index, err := s.index()
+	if err != nil {
+		return "", err
+	}
+
+	chartVersion, err := index.Get(dir, ref)
+	if err != nil {
+		return "", fmt.Errorf("chart %s %s not found in %s: %w", dir, ref, s.url, err)
+	}
+	return chartVersion.Version, nil
+}
+
+// Fetch downloads the chart version matching ref and unpacks it into dest
+// When the repository can't be reached, an exact version that is cached
+// is used as is
+func (s *helmRepoSource) Fetch(dir, ref, dest string) (*Download, error) {
+	version := ref
+	if version == "" {
+		version = "latest"
+	}
+	fmt.Printf("📥 Downloading chart: %s %s (%s)\n", dir, version, s.url)
+	chartRepo := s.repo + "/" + dir
+
+	file, version, err := s.fetch(dir, ref)
+	if err != nil {
+		entry, lookupErr := s.cache.Lookup(chartRepo, ref)
+		if lookupErr != nil || entry == nil {
+			return nil, err
+		}
+		fmt.Printf("⚠️  Download failed, using cached %s@%s: %v\n", chartRepo, ref, err)
+		if file, err = s.cache.OpenBlob(entry); err != nil {
+			return nil, err
+		}
+		version = ref
+	}
+	defer file.Close()
+
+	if err := extractPackage(file, dest, s.progress); err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("✅ Chart %s %s downloaded successfully to %s\n", dir, version, dest)
+	return &Download{Repo: chartRepo, Ref: version, Revision: version}, nil
+}
+
+// fetch returns the cached package of the chart version matching ref,
+// checked against the digest the index publishes
+func (s *helmRepoSource) fetch(name, ref string) (*os.File, string, error) {
+	index, err := s.index()
+	if err != nil {
+		return nil, "", err
+	}
+
+	chartVersion, err := index.Get(name, ref)
+	if err != nil {
+		return nil, "", fmt.Errorf("chart %s %s not found in %s: %w", name, ref, s.url, err)
+	}
+	if len(chartVersion.URLs) == 0 {
+		return nil, "", fmt.Errorf("chart %s %s has no download URL", name, chartVersion.Version)
+	}
+
+	chartURL, err := repo.ResolveReferenceURL(s.url, chartVersion.URLs[0])
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Published chart versions don't change
+	file, err := s.cache.Fetch(s.repo+"/"+name, chartVersion.Version, chartURL, true, s.client.Do, s.progress)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download chart %s %s: %w", name, chartVersion.Version, err)
+	}
+
+	if chartVersion.Digest != "" {
+		if err := checkSHA256(file, chartVersion.Digest); err != nil {
+			file.Close()
+			return nil, "", fmt.Errorf("chart %s %s: %w", name, chartVersion.Version, err)
+		}
+	}
+
+	return file, chartVersion.Version, nil
+}
+
+// index downloads and parses the repository's index.yaml
+func (s *helmRepoSource) index() (*repo.IndexFile, error) {
+	resp, err := s.client.Get(s.url + "/index.yaml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to download repository index: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download repository index: HTTP %d", resp.StatusCode)
+	}
+
+	// LoadIndexFile validates and sorts the entries, but only reads files
+	temp, err := os.CreateTemp("", "dbeerer-index-*.yaml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(temp.Name())
+
+	_, err = io.Copy(temp, io.LimitReader(resp.Body, 100<<20))
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to download repository index: %w", err)
+	}
+
+	index, err := repo.LoadIndexFile(filepath.Clean(temp.Name()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse repository index: %w", err)
+	}
+	return index, nil
+}
+
+// extractPackage unpacks a packaged chart, which holds a single "<name>/"
+// directory, into dest
+func extractPackage(r io.Reader, dest string, report progress.Reporter) error {
+	err := archive.ExtractTarGz(r, dest, archive.Options{
+		StripComponents: 1,
+		OnFile:          report.File,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to extract chart: %w", err)
+	}
+	return nil
+}
+
+// checkSHA256 compares the SHA-256 of a file with a hex digest and
+// rewinds the file
+func checkSHA256(file *os.File, expected string) error {
+	defer file.Seek(0, io.SeekStart)
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return err
+	}
+
+	actual := hex.EncodeToString(hash.Sum(nil))
+	if !strings.EqualFold(actual, strings.TrimPrefix(expected, "sha256:")) {
+		return errors.New("digest mismatch: the package doesn't match the repository index")
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package sources
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// localSource serves charts from a directory on this machine, handy while
+// developing a catalogue
+type localSource struct {
+	root string
+}
+
+// newLocalSource returns the source of a local directory
+func newLocalSource(root string) *localSource {
+	return &localSource{root: root}
+}
+
+// List returns the subdirectories holding a Chart.yaml
+func (s *localSource) List() ([]string, error) {
+	entries, err := os.ReadDir(s.root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", s.root, err)
+	}
+
+	var charts []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(s.root, entry.Name(), "Chart.yaml")); err == nil {
+			charts = append(charts, entry.Name())
+		}
+	}
+	sort.Strings(charts)
+
+	return charts, nil
+}
+
+// Resolve returns ref, local directories have no revisions
+func (s *localSource) Resolve(dir, ref string) (string, error) {
+	return ref, nil
+}
+
+// Fetch copies the chart directory to dest, ref is ignored
+func (s *localSource) Fetch(dir, ref, dest string) (*Download, error) {
+	fmt.Printf("📂 Copying chart: %s (%s)\n", dir, s.root)
+
+	src, err := s.chartDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := copyDir(src, dest); err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("✅ Chart copied to %s\n", dest)
+	return &Download{}, nil
+}
+
+// chartDir returns the path of dir below the root, refusing directories
+// that leave it, through .. or a symbolic link
+func (s *localSource) chartDir(dir string) (string, error) {
+	if !filepath.IsLocal(dir) {
+		return "", fmt.Errorf("chart directory %q is outside %s", dir, s.root)
+	}
+
+	root, err := filepath.EvalSymlinks(s.root)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", s.root, err)
+	}
+	src, err := filepath.EvalSymlinks(filepath.Join(root, dir))
+	if err != nil {
+		return "", fmt.Errorf("chart directory not found: %w", err)
+	}
+
+	relative, err := filepath.Rel(root, src)
+	if err != nil || !filepath.IsLocal(relative) {
+		return "", fmt.Errorf("chart directory %q is outside %s", dir, s.root)
+	}
+	return src, nil
+}
+
+// copyDir copies the regular files below src into dest, skipping links
+// and anything else that isn't a plain file or directory
+func copyDir(src, dest string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return fmt.Errorf("chart directory not found: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", src)
+	}
+
+	return filepath.WalkDir(src, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dest, rel)
+
+		switch {
+		case entry.IsDir():
+			if entry.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(target, 0755)
+		case entry.Type().IsRegular():
+			return copyFile(path, target)
+		default:
+			return nil
+		}
+	})
+}
+
+// copyFile copies a single regular file
+func copyFile(src, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to copy %s: %w", src, err)
+	}
+	return out.Close()
+}
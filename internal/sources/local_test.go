@@ -0,0 +1,47 @@
+package sources
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalSourceFetch(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "demo"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "demo", "Chart.yaml"), []byte("name: demo\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dest := filepath.Join(t.TempDir(), "demo")
+	if _, err := newLocalSource(root).Fetch("demo", "", dest); err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "Chart.yaml")); err != nil {
+		t.Errorf("Chart.yaml was not copied: %v", err)
+	}
+}
+
+func TestLocalSourceFetchOutsideRoot(t *testing.T) {
+	parent := t.TempDir()
+	root := filepath.Join(parent, "charts")
+	outside := filepath.Join(parent, "secrets")
+	for _, dir := range []string{root, outside} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "link")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	for _, dir := range []string{"../secrets", outside, "link", "demo/../../secrets"} {
+		_, err := newLocalSource(root).Fetch(dir, "", filepath.Join(t.TempDir(), "demo"))
+		if err == nil || !strings.Contains(err.Error(), "is outside") {
+			t.Errorf("Fetch(%q) error = %v, want outside error", dir, err)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package sources
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/cache"
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/config"
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/progress"
+	"github.com/Masterminds/semver/v3"
+	"helm.sh/helm/v3/pkg/registry"
+	orasregistry "oras.land/oras-go/v2/registry"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+// ociSource pulls packaged charts from an OCI registry with Helm's registry
+// client, e.g. oci://ghcr.io/devopsbeerer/charts/oidc-pkce:1.2.0
+// The link names a single chart, so the chart directory is ignored. A ref
+// overrides the link's tag: a version, a semver constraint, a tag or a
+// manifest digest
+type ociSource struct {
+	// name is the chart reference without tag or digest
+	name     string
+	registry string
+	tag      string
+	cfg      *config.Config
+	cache    *cache.Cache
+}
+
+// newOCISource returns the source of an oci:// chart reference
+func newOCISource(link string, cfg *config.Config) (*ociSource, error) {
+	ref, err := orasregistry.ParseReference(strings.TrimPrefix(link, "oci://"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid OCI chart reference '%s': %w", link, err)
+	}
+
+	return &ociSource{
+		name:     ref.Registry + "/" + ref.Repository,
+		registry: ref.Registry,
+		tag:      ref.Reference,
+		cfg:      cfg,
+		cache:    cache.New(),
+	}, nil
+}
+
+// List returns the name of the chart
+func (s *ociSource) List() ([]string, error) {
+	return []string{path.Base(s.name)}, nil
+}
+
+// Resolve returns the manifest digest of the chart at ref
+func (s *ociSource) Resolve(dir, ref string) (string, error) {
+	file, revision, _, err := s.fetch(ref)
+	if err != nil {
+		return "", err
+	}
+	file.Close()
+
+	return revision, nil
+}
+
+// Fetch pulls the chart at ref and unpacks it into dest
+func (s *ociSource) Fetch(dir, ref, dest string) (*Download, error) {
+	if ref == "" {
+		ref = s.tag
+	}
+	fmt.Printf("📥 Pulling chart: oci://%s\n", s.reference(ref))
+
+	file, revision, tag, err := s.fetch(ref)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if err := extractPackage(file, dest, progress.New(s.cfg)); err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("✅ Chart pulled successfully to %s (digest %s)\n", dest, revision)
+	return &Download{Repo: s.name, Ref: tag, Revision: revision}, nil
+}
+
+// fetch returns the cached package of the chart at ref, the manifest digest
+// it was pulled from and the tag or digest ref resolved to
+// Versions and digests are immutable, cached ones are used without asking
+// the registry
+func (s *ociSource) fetch(ref string) (*os.File, string, string, error) {
+	if ref == "" {
+		ref = s.tag
+	}
+
+	if isDigest(ref) || isVersion(ref) {
+		entry, err := s.cache.Lookup(s.name, ref)
+		if err != nil {
+			return nil, "", "", err
+		}
+		if entry != nil {
+			fmt.Printf("📦 Using cached %s@%s\n", s.name, ref)
+			revision := entry.ETag
+			if isDigest(ref) {
+				revision = ref
+			}
+			file, err := s.cache.OpenBlob(entry)
+			return file, revision, ref, err
+		}
+	}
+
+	client, err := s.client()
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to create registry client: %w", err)
+	}
+
+	tag, err := s.resolveTag(client, ref)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	result, err := client.Pull(s.reference(tag))
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to pull chart %s: %w", s.reference(tag), err)
+	}
+
+	entry, err := s.cache.Store(s.name, tag, result.Manifest.Digest, bytes.NewReader(result.Chart.Data))
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	file, err := s.cache.OpenBlob(entry)
+	return file, result.Manifest.Digest, tag, err
+}
+
+// resolveTag turns an empty ref into the latest version and a semver
+// constraint into the latest version matching it
+func (s *ociSource) resolveTag(client *registry.Client, ref string) (string, error) {
+	if isDigest(ref) || isVersion(ref) {
+		return ref, nil
+	}
+
+	var constraint *semver.Constraints
+	if ref != "" {
+		parsed, err := semver.NewConstraint(ref)
+		if err != nil {
+			// A tag like "latest"
+			return ref, nil
+		}
+		constraint = parsed
+	}
+
+	tags, err := client.Tags(s.name)
+	if err != nil {
+		return "", fmt.Errorf("failed to list versions of %s: %w", s.name, err)
+	}
+
+	// Tags are sorted newest first
+	for _, tag := range tags {
+		version, err := semver.NewVersion(tag)
+		if err == nil && (constraint == nil || constraint.Check(version)) {
+			return tag, nil
+		}
+	}
+
+	if ref == "" {
+		return "", fmt.Errorf("no chart versions found in %s", s.name)
+	}
+	return "", fmt.Errorf("no version of %s matches '%s'", s.name, ref)
+}
+
+// client returns a Helm registry client, logged in with the configured
+// credentials or else using the Helm and Docker credential stores
+func (s *ociSource) client() (*registry.Client, error) {
+	httpClient, err := newHTTPClient(s.cfg)
+	if err != nil {
+		return nil, err
+	}
+	options := []registry.ClientOption{
+		registry.ClientOptHTTPClient(httpClient),
+		registry.ClientOptEnableCache(true),
+	}
+
+	if s.cfg.Registry.PlainHTTP {
+		options = append(options, registry.ClientOptPlainHTTP())
+	}
+
+	if s.cfg.Registry.Username != "" {
+		options = append(options, registry.ClientOptAuthorizer(auth.Client{
+			Client: httpClient,
+			Credential: auth.StaticCredential(s.registry, auth.Credential{
+				Username: s.cfg.Registry.Username,
+				Password: s.cfg.Registry.Password,
+			}),
+			Cache: auth.NewCache(),
+		}))
+	}
+
+	return registry.NewClient(options...)
+}
+
+// reference returns the chart reference of a tag or digest
+func (s *ociSource) reference(tag string) string {
+	switch {
+	case tag == "":
+		return s.name
+	case isDigest(tag):
+		return s.name + "@" + tag
+	}
+	return s.name + ":" + tag
+}
+
+// isDigest reports whether ref is a manifest digest
+func isDigest(ref string) bool {
+	return strings.HasPrefix(ref, "sha256:")
+}
+
+// isVersion reports whether ref is an exact chart version
+func isVersion(ref string) bool {
+	_, err := semver.StrictNewVersion(ref)
+	return err == nil
+}
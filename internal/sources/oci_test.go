@@ -0,0 +1,211 @@
+package sources
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/config"
+	"github.com/distribution/distribution/v3/configuration"
+	"github.com/distribution/distribution/v3/registry/handlers"
+	_ "github.com/distribution/distribution/v3/registry/storage/driver/inmemory"
+	"github.com/sirupsen/logrus"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/registry"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+const (
+	testRegistryUser     = "beerer"
+	testRegistryPassword = "cheers"
+)
+
+// startRegistry serves an in-memory registry requiring basic auth and
+// pushes a demo chart in every version, it returns the registry host
+func startRegistry(t *testing.T, versions ...string) string {
+	t.Helper()
+
+	cfg := &configuration.Configuration{}
+	cfg.Storage = configuration.Storage{"inmemory": configuration.Parameters{}}
+	// The registry logs every 404 of a push, which is how pushes begin
+	logrus.SetOutput(io.Discard)
+	app := handlers.NewApp(context.Background(), cfg)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || user != testRegistryUser || password != testRegistryPassword {
+			w.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		app.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	client, err := registry.NewClient(
+		registry.ClientOptPlainHTTP(),
+		registry.ClientOptAuthorizer(auth.Client{
+			Credential: auth.StaticCredential(host, auth.Credential{
+				Username: testRegistryUser,
+				Password: testRegistryPassword,
+			}),
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, version := range versions {
+		data := packageChart(t, "demo", version)
+		if _, err := client.Push(data, host+"/charts/demo:"+version); err != nil {
+			t.Fatalf("failed to push demo %s: %v", version, err)
+		}
+	}
+
+	return host
+}
+
+// packageChart returns the packaged chart of a minimal chart
+func packageChart(t *testing.T, name, version string) []byte {
+	t.Helper()
+
+	path, err := chartutil.Save(&chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: name, Version: version},
+	}, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// testConfig isolates the chart cache and the Helm and Docker credential
+// stores, and talks plain HTTP to the test registry
+func testConfig(t *testing.T, withCredentials bool) *config.Config {
+	t.Helper()
+
+	home := t.TempDir()
+	t.Setenv("DBEERER_CACHE_DIR", filepath.Join(home, "cache"))
+	t.Setenv("HELM_REGISTRY_CONFIG", filepath.Join(home, "registry.json"))
+	t.Setenv("DOCKER_CONFIG", home)
+
+	cfg := &config.Config{}
+	cfg.Registry.PlainHTTP = true
+	if withCredentials {
+		cfg.Registry.Username = testRegistryUser
+		cfg.Registry.Password = testRegistryPassword
+	}
+	return cfg
+}
+
+// chartVersion returns the version of the chart unpacked in dir
+func chartVersion(t *testing.T, dir string) string {
+	t.Helper()
+
+	metadata, err := chartutil.LoadChartfile(filepath.Join(dir, "Chart.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return metadata.Version
+}
+
+func TestOCISourceFetch(t *testing.T) {
+	host := startRegistry(t, "0.1.0", "0.1.1", "0.2.0")
+
+	tests := []struct {
+		name string
+		link string
+		ref  string
+		want string
+	}{
+		{name: "tag in link", link: "oci://" + host + "/charts/demo:0.1.0", want: "0.1.0"},
+		{name: "ref overrides tag", link: "oci://" + host + "/charts/demo:0.1.0", ref: "0.2.0", want: "0.2.0"},
+		{name: "semver range", link: "oci://" + host + "/charts/demo", ref: "~0.1", want: "0.1.1"},
+		{name: "latest", link: "oci://" + host + "/charts/demo", want: "0.2.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := ForLink(tt.link, testConfig(t, true))
+			if err != nil {
+				t.Fatalf("ForLink() error = %v", err)
+			}
+
+			dest := filepath.Join(t.TempDir(), "demo")
+			download, err := source.Fetch("demo", tt.ref, dest)
+			if err != nil {
+				t.Fatalf("Fetch() error = %v", err)
+			}
+
+			if got := chartVersion(t, dest); got != tt.want {
+				t.Errorf("fetched version %s, want %s", got, tt.want)
+			}
+			if download.Ref != tt.want {
+				t.Errorf("Download.Ref = %s, want %s", download.Ref, tt.want)
+			}
+			if !isDigest(download.Revision) {
+				t.Errorf("Download.Revision = %s, want a manifest digest", download.Revision)
+			}
+
+			revision, err := source.Resolve("demo", tt.want)
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			if revision != download.Revision {
+				t.Errorf("Resolve() = %s, want %s", revision, download.Revision)
+			}
+		})
+	}
+}
+
+func TestOCISourceNeedsCredentials(t *testing.T) {
+	host := startRegistry(t, "0.1.0")
+
+	source, err := ForLink("oci://"+host+"/charts/demo:0.1.0", testConfig(t, false))
+	if err != nil {
+		t.Fatalf("ForLink() error = %v", err)
+	}
+
+	if _, err := source.Fetch("demo", "", filepath.Join(t.TempDir(), "demo")); err == nil {
+		t.Fatal("Fetch() without credentials succeeded")
+	}
+}
+
+func TestOCISourceDigest(t *testing.T) {
+	host := startRegistry(t, "0.1.0")
+	link := "oci://" + host + "/charts/demo:0.1.0"
+
+	source, err := ForLink(link, testConfig(t, true))
+	if err != nil {
+		t.Fatalf("ForLink() error = %v", err)
+	}
+	digest, err := source.Resolve("demo", "")
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	// A fresh cache makes the pull go to the registry by digest
+	source, err = ForLink(link, testConfig(t, true))
+	if err != nil {
+		t.Fatalf("ForLink() error = %v", err)
+	}
+	dest := filepath.Join(t.TempDir(), "demo")
+	download, err := source.Fetch("demo", digest, dest)
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if download.Revision != digest || chartVersion(t, dest) != "0.1.0" {
+		t.Errorf("Fetch(%s) = %+v, version %s", digest, download, chartVersion(t, dest))
+	}
+}
@@ -0,0 +1,111 @@
+package sources
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/config"
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/httpclient"
+)
+
+// ChartSource is where a scenario catalogue hosts its charts
+type ChartSource interface {
+	// List returns the charts the source offers: chart directories for
+	// repositories, chart names for Helm repositories
+	List() ([]string, error)
+	// Resolve returns the immutable revision of the chart in dir at ref, a
+	// commit SHA for Git hosts or a chart version for Helm repositories.
+	// Sources without revisions return ref unchanged
+	Resolve(dir, ref string) (string, error)
+	// Fetch writes the chart in dir, at ref, to dest
+	// An empty ref selects the source's default
+	Fetch(dir, ref, dest string) (*Download, error)
+}
+
+// Download describes what Fetch wrote
+type Download struct {
+	// Repo and Ref key the fetched tarball in the chart cache, Repo is
+	// empty for sources that aren't cached
+	Repo string
+	Ref  string
+	// Revision is the immutable revision Ref resolved to, e.g. a commit
+	// SHA, empty when the source didn't report one
+	Revision string
+}
+
+// Pin returns the ref that fetches exactly this download again
+func (d *Download) Pin() string {
+	if d.Revision != "" {
+		return d.Revision
+	}
+	return d.Ref
+}
+
+// ForLink returns the source serving a Scenario.HelmChart.Link
+//
+//	https://github.com/<owner>/<repo>            GitHub repository
+//	https://gitlab.example.com/<group>/<project> GitLab project (host starting with "gitlab.")
+//	https://.../<file>.tar.gz                    tarball, "{ref}" in the URL is replaced by the ref
+//	https://.../index.yaml, helm+https://...     Helm repository
+//	oci://<registry>/<repo>/<chart>:<version>    chart in an OCI registry
+//	git+https://..., git+ssh://..., ssh://...    Git repository cloned with git
+//	file:///path, /path, ./path                  local directory
+func ForLink(link string, cfg *config.Config) (ChartSource, error) {
+	if strings.HasPrefix(link, "oci://") {
+		return newOCISource(link, cfg)
+	}
+	if isLocalPath(link) {
+		return newLocalSource(strings.TrimPrefix(link, "file://")), nil
+	}
+
+	u, err := url.Parse(link)
+	if err != nil {
+		return nil, fmt.Errorf("invalid chart link '%s': %w", link, err)
+	}
+
+	switch {
+	case u.Scheme == "git" || u.Scheme == "ssh" || strings.HasPrefix(u.Scheme, "git+"):
+		return newGitSource(link, cfg)
+	case strings.HasPrefix(u.Scheme, "helm+"):
+		u.Scheme = strings.TrimPrefix(u.Scheme, "helm+")
+		return newHelmRepoSource(u, cfg)
+	case u.Scheme != "https" && u.Scheme != "http":
+		return nil, fmt.Errorf("unsupported chart link '%s'", link)
+	case strings.HasSuffix(u.Path, "/index.yaml"):
+		u.Path = strings.TrimSuffix(u.Path, "/index.yaml")
+		return newHelmRepoSource(u, cfg)
+	case isTarball(u.Path):
+		return newTarballSource(link, cfg)
+	case u.Host == "github.com":
+		return newGitHubSource(link, cfg)
+	case u.Host == "gitlab.com" || strings.HasPrefix(u.Host, "gitlab."):
+		return newGitLabSource(u, cfg)
+	}
+
+	return nil, fmt.Errorf("can't tell which kind of chart source '%s' is, link a tarball, an index.yaml or use git+https://", link)
+}
+
+// isLocalPath reports whether link points to a directory on this machine
+func isLocalPath(link string) bool {
+	return strings.HasPrefix(link, "file://") || filepath.IsAbs(link) ||
+		strings.HasPrefix(link, "./") || strings.HasPrefix(link, "../")
+}
+
+// isTarball reports whether a URL path names a gzipped tarball
+func isTarball(path string) bool {
+	return strings.HasSuffix(path, ".tar.gz") || strings.HasSuffix(path, ".tgz")
+}
+
+// repoKey identifies a remote source in the chart cache, the URL
+// without scheme, credentials and query
+func repoKey(u *url.URL) string {
+	return u.Host + strings.TrimSuffix(u.Path, "/")
+}
+
+// newHTTPClient returns the client sources download with
+func newHTTPClient(cfg *config.Config) (*http.Client, error) {
+	return httpclient.New(cfg, cfg.Timeouts.Download)
+}
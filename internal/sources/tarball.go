@@ -0,0 +1,315 @@
+package sources
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/archive"
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/cache"
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/config"
+	"github.com/DevOpsBeerer/dbeerer-cli/internal/progress"
+)
+
+// refPlaceholder in a tarball URL is replaced by the ref to fetch
+const refPlaceholder = "{ref}"
+
+// tarballSource fetches charts from a gzipped tarball served over HTTPS,
+// like the archives of GitLab, Gitea or any web server
+type tarballSource struct {
+	// url may hold refPlaceholder
+	url        string
+	repo       string
+	defaultRef string
+	client     *http.Client
+	cache      *cache.Cache
+	progress   progress.Reporter
+	// token is sent as a Bearer token, only to hosts it belongs to
+	token string
+	// resolve turns refs into commit SHAs, nil when the host can't
+	resolve func(ref string) (string, error)
+}
+
+// newTarballSource returns the source of a tarball URL
+func newTarballSource(link string, cfg *config.Config) (*tarballSource, error) {
+	client, err := newHTTPClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	source := &tarballSource{
+		url:      link,
+		client:   client,
+		cache:    cache.New(),
+		progress: progress.New(cfg),
+	}
+
+	if u, err := url.Parse(strings.ReplaceAll(link, refPlaceholder, "")); err == nil {
+		u.Path = path.Clean(u.Path)
+		source.repo = repoKey(u)
+	}
+	if source.hasRef() {
+		source.defaultRef = cfg.ChartRef
+	}
+
+	return source, nil
+}
+
+// newGitLabSource returns the source of a GitLab project URL, fetched
+// through the repository archive API so that gitlabToken opens private
+// projects
+func newGitLabSource(u *url.URL, cfg *config.Config) (*tarballSource, error) {
+	project := strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+	if !strings.Contains(project, "/") {
+		return nil, fmt.Errorf("'%s' is not a GitLab project URL", u)
+	}
+
+	api := fmt.Sprintf("%s://%s/api/v4/projects/%s/repository", u.Scheme, u.Host, url.PathEscape(project))
+	source, err := newTarballSource(api+"/archive.tar.gz?sha="+refPlaceholder, cfg)
+	if err != nil {
+		return nil, err
+	}
+	source.repo = u.Host + "/" + project
+	source.token = cfg.GitLabToken
+
+	source.resolve = func(ref string) (string, error) {
+		req, err := http.NewRequest(http.MethodGet, api+"/commits/"+url.PathEscape(ref), nil)
+		if err != nil {
+			return "", err
+		}
+		resp, err := source.do(req)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve ref '%s': %w", ref, err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode == http.StatusNotFound {
+			return "", fmt.Errorf("ref '%s' not found in %s", ref, project)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("failed to resolve ref '%s': HTTP %d", ref, resp.StatusCode)
+		}
+
+		var commit struct {
+			ID string `json:"id"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&commit); err != nil {
+			return "", fmt.Errorf("failed to parse commit: %w", err)
+		}
+		return commit.ID, nil
+	}
+
+	return source, nil
+}
+
+// List returns the directories of the tarball holding a Chart.yaml
+func (s *tarballSource) List() ([]string, error) {
+	file, ref, err := s.fetch("")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	charts, err := chartDirs(file)
+	if err != nil {
+		return nil, err
+	}
+	if len(charts) == 0 {
+		return nil, fmt.Errorf("no charts found in %s@%s", s.repo, ref)
+	}
+	return charts, nil
+}
+
+// Resolve returns the commit SHA of ref when the host can tell
+func (s *tarballSource) Resolve(dir, ref string) (string, error) {
+	if ref == "" {
+		ref = s.defaultRef
+	}
+	if err := s.checkRef(ref); err != nil {
+		return "", err
+	}
+	if s.resolve == nil || cache.IsCommitSHA(ref) {
+		return ref, nil
+	}
+	return s.resolve(ref)
+}
+
+// Fetch extracts the chart directory from the tarball at ref
+func (s *tarballSource) Fetch(dir, ref, dest string) (*Download, error) {
+	fmt.Printf("📥 Downloading chart: %s (%s)\n", dir, s.repo)
+
+	file, ref, err := s.fetch(ref)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	revision, err := extractChart(file, dir, dest, s.progress)
+	if err != nil {
+		return nil, err
+	}
+
+	// Without a placeholder the commit can't be fetched again, so it
+	// mustn't be pinned
+	if !s.hasRef() {
+		revision = ""
+	}
+
+	fmt.Printf("✅ Chart downloaded successfully to %s\n", dest)
+	return &Download{Repo: s.repo, Ref: ref, Revision: revision}, nil
+}
+
+// fetch returns the cached tarball at ref and the ref it used
+func (s *tarballSource) fetch(ref string) (*os.File, string, error) {
+	if ref == "" {
+		ref = s.defaultRef
+	}
+	if err := s.checkRef(ref); err != nil {
+		return nil, "", err
+	}
+
+	tarballURL := strings.ReplaceAll(s.url, refPlaceholder, url.PathEscape(ref))
+	file, err := s.cache.Fetch(s.repo, ref, tarballURL, cache.IsCommitSHA(ref), s.do, s.progress)
+	if errors.Is(err, cache.ErrNotFound) && ref != "" {
+		return nil, "", fmt.Errorf("ref '%s' not found in %s", ref, s.repo)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download %s: %w", s.repo, err)
+	}
+	return file, ref, nil
+}
+
+// hasRef reports whether the URL selects the ref through refPlaceholder
+func (s *tarballSource) hasRef() bool {
+	return strings.Contains(s.url, refPlaceholder)
+}
+
+// checkRef rejects a ref the URL can't select instead of ignoring it
+func (s *tarballSource) checkRef(ref string) error {
+	if ref != "" && !s.hasRef() {
+		return fmt.Errorf("%s has no %s placeholder, it can't fetch ref '%s'", s.url, refPlaceholder, ref)
+	}
+	return nil
+}
+
+// do sends a request with the source's token, if any
+// The client drops the Authorization header on redirects to other hosts
+func (s *tarballSource) do(req *http.Request) (*http.Response, error) {
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	return s.client.Do(req)
+}
+
+// extractChart extracts the chart in dir from a cached tarball, dropping
+// the top-level directory archives of Git hosts wrap everything in
+// It returns the commit recorded by 'git archive', if any
+func extractChart(file *os.File, dir, dest string, report progress.Reporter) (string, error) {
+	strip, err := commonPrefixDepth(file)
+	if err != nil {
+		return "", err
+	}
+
+	revision := ""
+	err = archive.ExtractTarGz(file, dest, archive.Options{
+		StripComponents: strip,
+		Dir:             dir,
+		OnFile:          report.File,
+		OnGlobalHeader: func(records map[string]string) {
+			if cache.IsCommitSHA(records["comment"]) {
+				revision = records["comment"]
+			}
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to extract chart: %w", err)
+	}
+
+	return revision, nil
+}
+
+// chartDirs returns the directories of a cached tarball holding a
+// Chart.yaml, below the top-level directory if there is one
+func chartDirs(file *os.File) ([]string, error) {
+	strip, err := commonPrefixDepth(file)
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := tarNames(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var charts []string
+	for _, name := range names {
+		parts := strings.Split(strings.TrimPrefix(name, "./"), "/")
+		if len(parts) == strip+2 && parts[strip+1] == "Chart.yaml" {
+			charts = append(charts, parts[strip])
+		}
+	}
+	sort.Strings(charts)
+
+	return charts, nil
+}
+
+// commonPrefixDepth returns 1 when every entry of the tarball lives in the
+// same top-level directory, 0 otherwise, and rewinds the file
+func commonPrefixDepth(file *os.File) (int, error) {
+	names, err := tarNames(file)
+	if err != nil {
+		return 0, err
+	}
+
+	top := ""
+	for _, name := range names {
+		first, _, nested := strings.Cut(strings.TrimPrefix(name, "./"), "/")
+		if !nested || (top != "" && first != top) {
+			return 0, nil
+		}
+		top = first
+	}
+
+	if top == "" {
+		return 0, nil
+	}
+	return 1, nil
+}
+
+// tarNames returns the names of the regular files and directories of a
+// gzipped tarball, directories ending in "/", and rewinds the file
+func tarNames(file *os.File) ([]string, error) {
+	defer file.Seek(0, io.SeekStart)
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+	}
+	defer gzipReader.Close()
+
+	var names []string
+	tarReader := tar.NewReader(gzipReader)
+	for len(names) < archive.DefaultLimits.MaxEntries {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tarball: %w", err)
+		}
+		if header.Typeflag == tar.TypeReg || header.Typeflag == tar.TypeDir {
+			names = append(names, header.Name)
+		}
+	}
+
+	return names, nil
+}
@@ -0,0 +1,258 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+)
+
+// +k8s:deepcopy-gen=false
+
+// Client is a typed client for the devopsbeerer.ch/v1alpha1 resources
+// Both resources are cluster-scoped
+type Client struct {
+	dynamicClient dynamic.Interface
+}
+
+// ScenarioDefinitionInterface reads and writes ScenarioDefinitions
+type ScenarioDefinitionInterface interface {
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*ScenarioDefinition, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*ScenarioDefinitionList, error)
+	Create(ctx context.Context, definition *ScenarioDefinition, opts metav1.CreateOptions) (*ScenarioDefinition, error)
+	Update(ctx context.Context, definition *ScenarioDefinition, opts metav1.UpdateOptions) (*ScenarioDefinition, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+}
+
+// ActiveScenarioInterface reads and writes ActiveScenarios
+type ActiveScenarioInterface interface {
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*ActiveScenario, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*ActiveScenarioList, error)
+	Create(ctx context.Context, active *ActiveScenario, opts metav1.CreateOptions) (*ActiveScenario, error)
+	Update(ctx context.Context, active *ActiveScenario, opts metav1.UpdateOptions) (*ActiveScenario, error)
+	UpdateStatus(ctx context.Context, active *ActiveScenario, opts metav1.UpdateOptions) (*ActiveScenario, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+}
+
+// NewForConfig creates a client for the given REST config
+func NewForConfig(config *rest.Config) (*Client, error) {
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+	return New(dynamicClient), nil
+}
+
+// New creates a client on top of an existing dynamic client
+func New(dynamicClient dynamic.Interface) *Client {
+	return &Client{dynamicClient: dynamicClient}
+}
+
+// ScenarioDefinitions returns the ScenarioDefinition client
+func (c *Client) ScenarioDefinitions() ScenarioDefinitionInterface {
+	return &scenarioDefinitions{resource: c.dynamicClient.Resource(ScenarioDefinitionsResource)}
+}
+
+// ActiveScenarios returns the ActiveScenario client
+func (c *Client) ActiveScenarios() ActiveScenarioInterface {
+	return &activeScenarios{resource: c.dynamicClient.Resource(ActiveScenariosResource)}
+}
+
+type scenarioDefinitions struct {
+	resource dynamic.NamespaceableResourceInterface
+}
+
+func (c *scenarioDefinitions) Get(ctx context.Context, name string, opts metav1.GetOptions) (*ScenarioDefinition, error) {
+	obj, err := c.resource.Get(ctx, name, opts)
+	if err != nil {
+		return nil, err
+	}
+	result := &ScenarioDefinition{}
+	return result, fromUnstructured(obj, result)
+}
+
+func (c *scenarioDefinitions) List(ctx context.Context, opts metav1.ListOptions) (*ScenarioDefinitionList, error) {
+	list, err := c.resource.List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ScenarioDefinitionList{ListMeta: metav1.ListMeta{ResourceVersion: list.GetResourceVersion()}}
+	result.Items = make([]ScenarioDefinition, 0, len(list.Items))
+	var decodeErr *DecodeError
+	for i := range list.Items {
+		var item ScenarioDefinition
+		if err := fromUnstructured(&list.Items[i], &item); err != nil {
+			decodeErr = decodeErr.add(list.Items[i].GetName(), err)
+			continue
+		}
+		result.Items = append(result.Items, item)
+	}
+	if decodeErr != nil {
+		return result, decodeErr
+	}
+	return result, nil
+}
+
+func (c *scenarioDefinitions) Create(ctx context.Context, definition *ScenarioDefinition, opts metav1.CreateOptions) (*ScenarioDefinition, error) {
+	obj, err := toUnstructured(definition, "ScenarioDefinition")
+	if err != nil {
+		return nil, err
+	}
+	created, err := c.resource.Create(ctx, obj, opts)
+	if err != nil {
+		return nil, err
+	}
+	result := &ScenarioDefinition{}
+	return result, fromUnstructured(created, result)
+}
+
+func (c *scenarioDefinitions) Update(ctx context.Context, definition *ScenarioDefinition, opts metav1.UpdateOptions) (*ScenarioDefinition, error) {
+	obj, err := toUnstructured(definition, "ScenarioDefinition")
+	if err != nil {
+		return nil, err
+	}
+	updated, err := c.resource.Update(ctx, obj, opts)
+	if err != nil {
+		return nil, err
+	}
+	result := &ScenarioDefinition{}
+	return result, fromUnstructured(updated, result)
+}
+
+func (c *scenarioDefinitions) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return c.resource.Delete(ctx, name, opts)
+}
+
+type activeScenarios struct {
+	resource dynamic.NamespaceableResourceInterface
+}
+
+func (c *activeScenarios) Get(ctx context.Context, name string, opts metav1.GetOptions) (*ActiveScenario, error) {
+	obj, err := c.resource.Get(ctx, name, opts)
+	if err != nil {
+		return nil, err
+	}
+	result := &ActiveScenario{}
+	return result, fromUnstructured(obj, result)
+}
+
+func (c *activeScenarios) List(ctx context.Context, opts metav1.ListOptions) (*ActiveScenarioList, error) {
+	list, err := c.resource.List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ActiveScenarioList{ListMeta: metav1.ListMeta{ResourceVersion: list.GetResourceVersion()}}
+	result.Items = make([]ActiveScenario, 0, len(list.Items))
+	var decodeErr *DecodeError
+	for i := range list.Items {
+		var item ActiveScenario
+		if err := fromUnstructured(&list.Items[i], &item); err != nil {
+			decodeErr = decodeErr.add(list.Items[i].GetName(), err)
+			continue
+		}
+		result.Items = append(result.Items, item)
+	}
+	if decodeErr != nil {
+		return result, decodeErr
+	}
+	return result, nil
+}
+
+func (c *activeScenarios) Create(ctx context.Context, active *ActiveScenario, opts metav1.CreateOptions) (*ActiveScenario, error) {
+	obj, err := toUnstructured(active, "ActiveScenario")
+	if err != nil {
+		return nil, err
+	}
+	created, err := c.resource.Create(ctx, obj, opts)
+	if err != nil {
+		return nil, err
+	}
+	result := &ActiveScenario{}
+	return result, fromUnstructured(created, result)
+}
+
+func (c *activeScenarios) Update(ctx context.Context, active *ActiveScenario, opts metav1.UpdateOptions) (*ActiveScenario, error) {
+	obj, err := toUnstructured(active, "ActiveScenario")
+	if err != nil {
+		return nil, err
+	}
+	updated, err := c.resource.Update(ctx, obj, opts)
+	if err != nil {
+		return nil, err
+	}
+	result := &ActiveScenario{}
+	return result, fromUnstructured(updated, result)
+}
+
+func (c *activeScenarios) UpdateStatus(ctx context.Context, active *ActiveScenario, opts metav1.UpdateOptions) (*ActiveScenario, error) {
+	obj, err := toUnstructured(active, "ActiveScenario")
+	if err != nil {
+		return nil, err
+	}
+	updated, err := c.resource.UpdateStatus(ctx, obj, opts)
+	if err != nil {
+		return nil, err
+	}
+	result := &ActiveScenario{}
+	return result, fromUnstructured(updated, result)
+}
+
+func (c *activeScenarios) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return c.resource.Delete(ctx, name, opts)
+}
+
+// toUnstructured converts a typed object, filling in its apiVersion and kind
+func toUnstructured(obj runtime.Object, kind string) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", kind, err)
+	}
+
+	u := &unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   SchemeGroupVersion.Group,
+		Version: SchemeGroupVersion.Version,
+		Kind:    kind,
+	})
+	return u, nil
+}
+
+// DecodeError is returned by List along with the items that could be
+// decoded when some items couldn't
+type DecodeError struct {
+	// Items maps the names of the undecodable items to their error
+	Items map[string]error
+}
+
+// Error implements error
+func (e *DecodeError) Error() string {
+	names := make([]string, 0, len(e.Items))
+	for name := range e.Items {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Sprintf("failed to decode %s", strings.Join(names, ", "))
+}
+
+// add records the error of an item, allocating e when nil
+func (e *DecodeError) add(name string, err error) *DecodeError {
+	if e == nil {
+		e = &DecodeError{Items: map[string]error{}}
+	}
+	e.Items[name] = err
+	return e
+}
+
+// fromUnstructured decodes an unstructured object into a typed one
+func fromUnstructured(u *unstructured.Unstructured, obj runtime.Object) error {
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), obj)
+}
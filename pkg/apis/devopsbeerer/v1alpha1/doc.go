@@ -0,0 +1,8 @@
+// Package v1alpha1 contains the Go types and a typed client for the
+// devopsbeerer.ch/v1alpha1 API group: ScenarioDefinition, which describes a
+// playground scenario, and ActiveScenario, which selects the scenario the
+// operator should run.
+//
+// +k8s:deepcopy-gen=package
+// +groupName=devopsbeerer.ch
+package v1alpha1
@@ -0,0 +1,43 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GroupName is the API group of the DevOpsBeerer resources
+const GroupName = "devopsbeerer.ch"
+
+// SchemeGroupVersion is the group version used to register these objects
+var SchemeGroupVersion = schema.GroupVersion{Group: GroupName, Version: "v1alpha1"}
+
+// Resources served by this group version
+var (
+	ScenarioDefinitionsResource = SchemeGroupVersion.WithResource("scenariodefinitions")
+	ActiveScenariosResource     = SchemeGroupVersion.WithResource("activescenarios")
+)
+
+var (
+	// SchemeBuilder registers the types of this group version
+	SchemeBuilder = runtime.NewSchemeBuilder(addKnownTypes)
+	// AddToScheme adds the types of this group version to a scheme
+	AddToScheme = SchemeBuilder.AddToScheme
+)
+
+// Resource takes an unqualified resource and returns a group qualified GroupResource
+func Resource(resource string) schema.GroupResource {
+	return SchemeGroupVersion.WithResource(resource).GroupResource()
+}
+
+// addKnownTypes adds the list of known types to the scheme
+func addKnownTypes(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(SchemeGroupVersion,
+		&ScenarioDefinition{},
+		&ScenarioDefinitionList{},
+		&ActiveScenario{},
+		&ActiveScenarioList{},
+	)
+	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
+	return nil
+}
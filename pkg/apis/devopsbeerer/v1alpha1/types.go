@@ -0,0 +1,97 @@
+package v1alpha1
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultActiveScenarioName is the name of the singleton ActiveScenario
+// the operator watches
+const DefaultActiveScenarioName = "current-playground-scenario"
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ScenarioDefinition describes a playground scenario and where its chart lives
+type ScenarioDefinition struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ScenarioDefinitionSpec `json:"spec"`
+}
+
+// ScenarioDefinitionSpec is the catalogue entry of a scenario
+type ScenarioDefinitionSpec struct {
+	ID          string        `json:"id"`
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	Tags        []string      `json:"tags,omitempty"`
+	Features    []string      `json:"features,omitempty"`
+	HelmChart   HelmChartSpec `json:"helmChart"`
+}
+
+// HelmChartSpec locates a scenario's Helm chart
+type HelmChartSpec struct {
+	// Link is the repository the chart is fetched from
+	Link string `json:"link"`
+	// Dir is the chart directory inside the repository
+	Dir string `json:"dir,omitempty"`
+	// Ref is the branch, tag or commit SHA to fetch, the CLI's configured
+	// ref when empty
+	Ref string `json:"ref,omitempty"`
+	// Digest is the expected "sha256:<hex>" digest of the chart directory
+	Digest string `json:"digest,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ScenarioDefinitionList is a list of ScenarioDefinitions
+type ScenarioDefinitionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ScenarioDefinition `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ActiveScenario selects the scenario the operator runs
+type ActiveScenario struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ActiveScenarioSpec   `json:"spec"`
+	Status ActiveScenarioStatus `json:"status,omitempty"`
+}
+
+// ActiveScenarioSpec is the desired scenario
+type ActiveScenarioSpec struct {
+	ScenarioID string `json:"scenarioId"`
+	// Values are Helm values overriding the chart defaults
+	Values *apiextensionsv1.JSON `json:"values,omitempty"`
+}
+
+// ActiveScenarioStatus is the observed state of the running scenario
+// Times are RFC3339 timestamps
+type ActiveScenarioStatus struct {
+	Phase              string `json:"phase,omitempty"`
+	Message            string `json:"message,omitempty"`
+	ScenarioName       string `json:"scenarioName,omitempty"`
+	HelmReleaseName    string `json:"helmReleaseName,omitempty"`
+	ChartCommit        string `json:"chartCommit,omitempty"`
+	StartTime          string `json:"startTime,omitempty"`
+	LastTransitionTime string `json:"lastTransitionTime,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ActiveScenarioList is a list of ActiveScenarios
+type ActiveScenarioList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ActiveScenario `json:"items"`
+}